@@ -55,3 +55,14 @@ func BestCoding(input string, isGSM7Supported bool) DataCoding {
 		return BestAllCoding(input, isGSM7Supported)
 	}
 }
+
+// BestNationalCoding is like BestCoding but tries the national language tables of languages
+// before giving up on GSM7, see BestNationalTables. The tables must be announced in the UDH.
+func BestNationalCoding(input string, isGSM7Supported bool, languages ...Language) (DataCoding, NationalTables) {
+	if isGSM7Supported {
+		if tables, ok := BestNationalTables(input, languages...); ok {
+			return GSM7BitCoding, tables
+		}
+	}
+	return BestCoding(input, isGSM7Supported), NationalTables{}
+}
@@ -24,20 +24,19 @@ func (c DataCoding) String() string {
 	return fmt.Sprintf("%08b", byte(c))
 }
 
-// MessageWaitingInfo ...
+// MessageWaitingInfo decodes the groups 0xC0-0xEF, kind is -1 for other codings
 func (c DataCoding) MessageWaitingInfo() (coding DataCoding, active bool, kind int) {
 	kind = -1
 	coding = NoCoding
 	switch c >> 4 & 0b1111 {
-	case 0b1100:
-	case 0b1101:
+	case 0b1100, 0b1101: // discard or store, both GSM7
 		coding = GSM7BitCoding
 	case 0b1110:
 		coding = UCS2Coding
 	default:
 		return
 	}
-	active = c>>3 == 1
+	active = c>>3&0b1 == 1
 	kind = int(c & 0b11)
 	return
 }
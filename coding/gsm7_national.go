@@ -0,0 +1,214 @@
+package coding
+
+// Language is a national language identifier, see 3GPP TS 23.038, section 6.2.1.2.4
+type Language byte
+
+// The languages with tables, the identifiers of the others are announced in the UDH but not supported
+const (
+	LanguageDefault    Language = 0
+	LanguageTurkish    Language = 1
+	LanguageSpanish    Language = 2
+	LanguagePortuguese Language = 3
+	LanguageBengali    Language = 4
+	LanguageHindi      Language = 6
+)
+
+// UDH information elements announcing a national language table, see 3GPP TS 23.040, section 9.2.3.24.15-16
+const (
+	IENationalSingleShift  byte = 0x24
+	IENationalLockingShift byte = 0x25
+)
+
+var lockingTables = map[Language]*[128]rune{
+	LanguageTurkish:    &turkishLocking,
+	LanguagePortuguese: &portugueseLocking,
+	LanguageBengali:    &bengaliLocking,
+	LanguageHindi:      &hindiLocking,
+}
+
+var singleTables = map[Language]map[byte]rune{
+	LanguageTurkish:    turkishSingle,
+	LanguageSpanish:    spanishSingle,
+	LanguagePortuguese: portugueseSingle,
+	LanguageBengali:    bengaliSingle,
+	LanguageHindi:      hindiSingle,
+}
+
+// reverse mappings, built in init
+var (
+	lockingToGSM7 = make(map[Language]map[rune]byte)
+	singleToGSM7  = make(map[Language]map[rune]byte)
+)
+
+func init() {
+	lockingToGSM7[LanguageDefault] = baseGSM7
+	singleToGSM7[LanguageDefault] = extendedGSM7
+	singleTables[LanguageDefault] = gsm7ToExtended
+
+	for lang, table := range lockingTables {
+		m := make(map[rune]byte)
+		for i, r := range table {
+			if r != 0 {
+				m[r] = byte(i)
+			}
+		}
+		lockingToGSM7[lang] = m
+	}
+	for lang, table := range singleTables {
+		if lang == LanguageDefault {
+			continue
+		}
+		m := make(map[rune]byte)
+		for b, r := range table {
+			if prev, ok := m[r]; !ok || b < prev {
+				m[r] = b
+			}
+		}
+		singleToGSM7[lang] = m
+	}
+}
+
+// NationalTables selects the locking and single shift tables of a GSM7 message,
+// the zero value is the default alphabet and its extension table
+type NationalTables struct {
+	Locking Language
+	Single  Language
+}
+
+// Supported reports whether both tables are known
+func (t NationalTables) Supported() bool {
+	_, locking := lockingToGSM7[t.Locking]
+	_, single := singleToGSM7[t.Single]
+	return locking && single
+}
+
+// UDHLen returns the octets the tables add to the UDH, without the UDHL octet
+func (t NationalTables) UDHLen() (n int) {
+	if t.Locking != LanguageDefault {
+		n += 3
+	}
+	if t.Single != LanguageDefault {
+		n += 3
+	}
+	return
+}
+
+// Septets returns how many septets r takes, zero if it can't be encoded
+func (t NationalTables) Septets(r rune) int {
+	if _, ok := lockingToGSM7[t.Locking][r]; ok {
+		return 1
+	}
+	if _, ok := singleToGSM7[t.Single][r]; ok {
+		return 2
+	}
+	return 0
+}
+
+// Validate reports whether text is made up entirely of characters of the tables
+func (t NationalTables) Validate(text string) bool {
+	for _, r := range text {
+		if t.Septets(r) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Encode encodes text to one septet per byte, characters of the single shift table
+// are preceded by the escape. Unknown characters are replaced with '?'.
+func (t NationalTables) Encode(text string) []byte {
+	locking, single := lockingToGSM7[t.Locking], singleToGSM7[t.Single]
+	out := make([]byte, 0, len(text))
+	for _, r := range text {
+		if b, ok := locking[r]; ok {
+			out = append(out, b)
+		} else if b, ok = single[r]; ok {
+			out = append(out, esc, b)
+		} else {
+			out = append(out, unknown)
+		}
+	}
+	return out
+}
+
+// Decode is the inverse of Encode
+func (t NationalTables) Decode(septets []byte) string {
+	locking, single := lockingTables[t.Locking], singleTables[t.Single]
+	out := make([]rune, 0, len(septets))
+	for i := 0; i < len(septets); i++ {
+		b := septets[i] & 0x7F
+		var r rune
+		if b == esc && i+1 < len(septets) {
+			i++
+			r = single[septets[i]&0x7F]
+		} else if locking != nil {
+			r = locking[b]
+		} else {
+			r = gsm7ToBase[b]
+		}
+		if r == 0 {
+			r = rune(unknown)
+		}
+		out = append(out, r)
+	}
+	return string(out)
+}
+
+// Splitter counts the bits of a character in the tables
+func (t NationalTables) Splitter() Splitter {
+	return func(r rune) int {
+		if n := t.Septets(r); n > 0 {
+			return 7 * n
+		}
+		return 7
+	}
+}
+
+// Split cuts text into segments next to a UDH of udhLength octets, the IEs of the tables included
+func (t NationalTables) Split(text string, udhLength int) []string {
+	if n := t.UDHLen(); n > 0 {
+		if udhLength == 0 {
+			n++ // UDHL
+		}
+		udhLength += n
+	}
+	return t.Splitter().Split(text, MaxOctets, udhLength)
+}
+
+// concatLen is the UDHL and the 8-bit reference concatenation IE every part of a long message carries
+const concatLen = 6
+
+// Parts returns how many parts text takes, split as SplitSubmitSM does: a second pass makes
+// room for the concatenation IE once there is more than one segment
+func (t NationalTables) Parts(text string) int {
+	n := len(t.Split(text, 0))
+	if n > 1 {
+		n = len(t.Split(text, concatLen))
+	}
+	return n
+}
+
+// BestNationalTables picks the tables among languages that send text in the fewest parts,
+// the UDH they need included, and the smaller UDH among equals. It returns false when no table covers text.
+func BestNationalTables(text string, languages ...Language) (best NationalTables, ok bool) {
+	candidates := []NationalTables{{}}
+	for _, lang := range languages {
+		candidates = append(candidates,
+			NationalTables{Single: lang},
+			NationalTables{Locking: lang},
+			NationalTables{Locking: lang, Single: lang},
+		)
+	}
+
+	bestParts := -1
+	for _, t := range candidates {
+		if !t.Supported() || !t.Validate(text) {
+			continue
+		}
+		parts := t.Parts(text)
+		if bestParts == -1 || parts < bestParts || parts == bestParts && t.UDHLen() < best.UDHLen() {
+			best, bestParts, ok = t, parts, true
+		}
+	}
+	return
+}
@@ -0,0 +1,314 @@
+package coding
+
+// National language tables see 3GPP TS 23.038, annex A. Zero marks an undefined position.
+
+// turkishLocking see 3GPP TS 23.038, section A.3.1
+var turkishLocking = [128]rune{
+	'@', '£', '$', '¥', '€', 'é', 'ù', 'ı', 'ò', 'Ç', '\n', 'Ğ', 'ğ', '\r', 'Å', 'å',
+	'Δ', '_', 'Φ', 'Γ', 'Λ', 'Ω', 'Π', 'Ψ', 'Σ', 'Θ', 'Ξ', 0, 'Ş', 'ş', 'ß', 'É',
+	' ', '!', '"', '#', '¤', '%', '&', '\'', '(', ')', '*', '+', ',', '-', '.', '/',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', '<', '=', '>', '?',
+	'İ', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+	'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 'Ä', 'Ö', 'Ñ', 'Ü', '§',
+	'ç', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+	'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 'ä', 'ö', 'ñ', 'ü', 'à',
+}
+
+// portugueseLocking see 3GPP TS 23.038, section A.3.3
+var portugueseLocking = [128]rune{
+	'@', '£', '$', '¥', 'ê', 'é', 'ú', 'í', 'ó', 'ç', '\n', 'Ô', 'ô', '\r', 'Á', 'á',
+	'Δ', '_', 'ª', 'Ç', 'À', '∞', '^', '\\', '€', 'Ó', '|', 0, 'Â', 'â', 'Ê', 'É',
+	' ', '!', '"', '#', 'º', '%', '&', '\'', '(', ')', '*', '+', ',', '-', '.', '/',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', '<', '=', '>', '?',
+	'Í', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+	'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', 'Ã', 'Õ', 'Ú', 'Ü', '§',
+	'~', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+	'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', 'ã', 'õ', '`', 'ü', 'à',
+}
+
+// bengaliLocking see 3GPP TS 23.038, section A.3.4
+var bengaliLocking = [128]rune{
+	'\u0981', '\u0982', '\u0983', '\u0985', '\u0986', '\u0987', '\u0988', '\u0989', '\u098A', '\u098B', '\n', '\u098C', 0, '\r', 0, '\u098F',
+	'\u0990', 0, 0, '\u0993', '\u0994', '\u0995', '\u0996', '\u0997', '\u0998', '\u0999', '\u099A', 0, '\u099B', '\u099C', '\u099D', '\u099E',
+	' ', '!', '\u099F', '\u09A0', '\u09A1', '\u09A2', '\u09A3', '\u09A4', ')', '(', '\u09A5', '\u09A6', ',', '\u09A7', '.', '\u09A8',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', 0, '\u09AA', '\u09AB', '?',
+	'\u09AC', '\u09AD', '\u09AE', '\u09AF', '\u09B0', 0, '\u09B2', 0, 0, 0, '\u09B6', '\u09B7', '\u09B8', '\u09B9', '\u09BC', '\u09BD',
+	'\u09BE', '\u09BF', '\u09C0', '\u09C1', '\u09C2', '\u09C3', '\u09C4', 0, 0, '\u09C7', '\u09C8', 0, 0, '\u09CB', '\u09CC', '\u09CD',
+	'\u09CE', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+	'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', '\u09D7', '\u09DC', '\u09DD', '\u09F0', '\u09F1',
+}
+
+// hindiLocking see 3GPP TS 23.038, section A.3.6
+var hindiLocking = [128]rune{
+	'\u0901', '\u0902', '\u0903', '\u0905', '\u0906', '\u0907', '\u0908', '\u0909', '\u090A', '\u090B', '\n', '\u090C', '\u090D', '\r', '\u090E', '\u090F',
+	'\u0910', '\u0911', '\u0912', '\u0913', '\u0914', '\u0915', '\u0916', '\u0917', '\u0918', '\u0919', '\u091A', 0, '\u091B', '\u091C', '\u091D', '\u091E',
+	' ', '!', '\u091F', '\u0920', '\u0921', '\u0922', '\u0923', '\u0924', ')', '(', '\u0925', '\u0926', ',', '\u0927', '.', '\u0928',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', '\u0929', '\u092A', '\u092B', '?',
+	'\u092C', '\u092D', '\u092E', '\u092F', '\u0930', '\u0931', '\u0932', '\u0933', '\u0934', '\u0935', '\u0936', '\u0937', '\u0938', '\u0939', '\u093C', '\u093D',
+	'\u093E', '\u093F', '\u0940', '\u0941', '\u0942', '\u0943', '\u0944', '\u0945', '\u0946', '\u0947', '\u0948', '\u0949', '\u094A', '\u094B', '\u094C', '\u094D',
+	'\u0950', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+	'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', '\u0972', '\u097B', '\u097C', '\u097E', '\u097F',
+}
+
+// turkishSingle see 3GPP TS 23.038, section A.2.1
+var turkishSingle = map[byte]rune{
+	0x0A: '\f',
+	0x14: '^',
+	0x28: '{',
+	0x29: '}',
+	0x2F: '\\',
+	0x3C: '[',
+	0x3D: '~',
+	0x3E: ']',
+	0x40: '|',
+	0x47: 'Ğ',
+	0x49: 'İ',
+	0x53: 'Ş',
+	0x63: 'ç',
+	0x65: '€',
+	0x67: 'ğ',
+	0x69: 'ı',
+	0x73: 'ş',
+}
+
+// spanishSingle see 3GPP TS 23.038, section A.2.2
+var spanishSingle = map[byte]rune{
+	0x09: 'ç',
+	0x0A: '\f',
+	0x14: '^',
+	0x28: '{',
+	0x29: '}',
+	0x2F: '\\',
+	0x3C: '[',
+	0x3D: '~',
+	0x3E: ']',
+	0x40: '|',
+	0x41: 'Á',
+	0x49: 'Í',
+	0x4F: 'Ó',
+	0x55: 'Ú',
+	0x61: 'á',
+	0x65: '€',
+	0x69: 'í',
+	0x6F: 'ó',
+	0x75: 'ú',
+}
+
+// portugueseSingle see 3GPP TS 23.038, section A.2.3
+var portugueseSingle = map[byte]rune{
+	0x05: 'ê',
+	0x09: 'ç',
+	0x0A: '\f',
+	0x0B: 'Ô',
+	0x0C: 'ô',
+	0x0E: 'Á',
+	0x0F: 'á',
+	0x12: 'Φ',
+	0x13: 'Γ',
+	0x14: '^',
+	0x15: 'Ω',
+	0x16: 'Π',
+	0x17: 'Ψ',
+	0x18: 'Σ',
+	0x19: 'Θ',
+	0x1F: 'Ê',
+	0x28: '{',
+	0x29: '}',
+	0x2F: '\\',
+	0x3C: '[',
+	0x3D: '~',
+	0x3E: ']',
+	0x40: '|',
+	0x41: 'À',
+	0x49: 'Í',
+	0x4F: 'Ó',
+	0x55: 'Ú',
+	0x5B: 'Ã',
+	0x5C: 'Õ',
+	0x61: 'Â',
+	0x65: '€',
+	0x69: 'í',
+	0x6F: 'ó',
+	0x75: 'ú',
+	0x7B: 'ã',
+	0x7C: 'õ',
+	0x7F: 'â',
+}
+
+// bengaliSingle see 3GPP TS 23.038, section A.2.4
+var bengaliSingle = map[byte]rune{
+	0x00: '@',
+	0x01: '£',
+	0x02: '$',
+	0x03: '¥',
+	0x04: '¿',
+	0x05: '"',
+	0x06: '¤',
+	0x07: '%',
+	0x08: '&',
+	0x09: '\'',
+	0x0A: '\f',
+	0x0B: '*',
+	0x0C: '+',
+	0x0E: '-',
+	0x0F: '/',
+	0x10: '<',
+	0x11: '=',
+	0x12: '>',
+	0x13: '¡',
+	0x14: '^',
+	0x15: '¡',
+	0x16: '_',
+	0x17: '#',
+	0x18: '*',
+	0x19: '\u09E6',
+	0x1A: '\u09E7',
+	0x1C: '\u09E8',
+	0x1D: '\u09E9',
+	0x1E: '\u09EA',
+	0x1F: '\u09EB',
+	0x20: '\u09EC',
+	0x21: '\u09ED',
+	0x22: '\u09EE',
+	0x23: '\u09EF',
+	0x24: '\u09DF',
+	0x25: '\u09E0',
+	0x26: '\u09E1',
+	0x27: '\u09E2',
+	0x28: '{',
+	0x29: '}',
+	0x2A: '\u09E3',
+	0x2B: '\u09F2',
+	0x2C: '\u09F3',
+	0x2D: '\u09F4',
+	0x2E: '\u09F5',
+	0x2F: '\\',
+	0x30: '\u09F6',
+	0x31: '\u09F7',
+	0x32: '\u09F8',
+	0x33: '\u09F9',
+	0x34: '\u09FA',
+	0x3C: '[',
+	0x3D: '~',
+	0x3E: ']',
+	0x40: '|',
+	0x41: 'A',
+	0x42: 'B',
+	0x43: 'C',
+	0x44: 'D',
+	0x45: 'E',
+	0x46: 'F',
+	0x47: 'G',
+	0x48: 'H',
+	0x49: 'I',
+	0x4A: 'J',
+	0x4B: 'K',
+	0x4C: 'L',
+	0x4D: 'M',
+	0x4E: 'N',
+	0x4F: 'O',
+	0x50: 'P',
+	0x51: 'Q',
+	0x52: 'R',
+	0x53: 'S',
+	0x54: 'T',
+	0x55: 'U',
+	0x56: 'V',
+	0x57: 'W',
+	0x58: 'X',
+	0x59: 'Y',
+	0x5A: 'Z',
+	0x65: '€',
+}
+
+// hindiSingle see 3GPP TS 23.038, section A.2.6
+var hindiSingle = map[byte]rune{
+	0x00: '@',
+	0x01: '£',
+	0x02: '$',
+	0x03: '¥',
+	0x04: '¿',
+	0x05: '"',
+	0x06: '¤',
+	0x07: '%',
+	0x08: '&',
+	0x09: '\'',
+	0x0A: '\f',
+	0x0B: '*',
+	0x0C: '+',
+	0x0E: '-',
+	0x0F: '/',
+	0x10: '<',
+	0x11: '=',
+	0x12: '>',
+	0x13: '¡',
+	0x14: '^',
+	0x15: '¡',
+	0x16: '_',
+	0x17: '#',
+	0x18: '*',
+	0x19: '\u0964',
+	0x1A: '\u0965',
+	0x1C: '\u0966',
+	0x1D: '\u0967',
+	0x1E: '\u0968',
+	0x1F: '\u0969',
+	0x20: '\u096A',
+	0x21: '\u096B',
+	0x22: '\u096C',
+	0x23: '\u096D',
+	0x24: '\u096E',
+	0x25: '\u096F',
+	0x26: '\u0951',
+	0x27: '\u0952',
+	0x28: '{',
+	0x29: '}',
+	0x2A: '\u0953',
+	0x2B: '\u0954',
+	0x2C: '\u0958',
+	0x2D: '\u0959',
+	0x2E: '\u095A',
+	0x2F: '\\',
+	0x30: '\u095B',
+	0x31: '\u095C',
+	0x32: '\u095D',
+	0x33: '\u095E',
+	0x34: '\u095F',
+	0x35: '\u0960',
+	0x36: '\u0961',
+	0x37: '\u0962',
+	0x38: '\u0963',
+	0x39: '\u0970',
+	0x3A: '\u0971',
+	0x3C: '[',
+	0x3D: '~',
+	0x3E: ']',
+	0x40: '|',
+	0x41: 'A',
+	0x42: 'B',
+	0x43: 'C',
+	0x44: 'D',
+	0x45: 'E',
+	0x46: 'F',
+	0x47: 'G',
+	0x48: 'H',
+	0x49: 'I',
+	0x4A: 'J',
+	0x4B: 'K',
+	0x4C: 'L',
+	0x4D: 'M',
+	0x4E: 'N',
+	0x4F: 'O',
+	0x50: 'P',
+	0x51: 'Q',
+	0x52: 'R',
+	0x53: 'S',
+	0x54: 'T',
+	0x55: 'U',
+	0x56: 'V',
+	0x57: 'W',
+	0x58: 'X',
+	0x59: 'Y',
+	0x5A: 'Z',
+	0x65: '€',
+}
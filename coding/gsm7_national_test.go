@@ -0,0 +1,57 @@
+package coding
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNationalTablesRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		tables NationalTables
+		text   string
+	}{
+		{NationalTables{}, "Hello {world} €5"},
+		{NationalTables{Locking: LanguageTurkish}, "Günaydın ŞĞİ çş"},
+		{NationalTables{Single: LanguageTurkish}, "Ağaç şiş €"},
+		{NationalTables{Locking: LanguageTurkish, Single: LanguageTurkish}, "İstanbul €{}"},
+		{NationalTables{Single: LanguageSpanish}, "Árbol ó Ú"},
+		{NationalTables{Locking: LanguagePortuguese, Single: LanguagePortuguese}, "Ação ê ô"},
+		{NationalTables{Locking: LanguageBengali, Single: LanguageBengali}, "আমার"},
+		{NationalTables{Locking: LanguageHindi, Single: LanguageHindi}, "नमस्ते"},
+	} {
+		if !tt.tables.Validate(tt.text) {
+			t.Errorf("%+v: %q not covered", tt.tables, tt.text)
+			continue
+		}
+		if got := tt.tables.Decode(tt.tables.Encode(tt.text)); got != tt.text {
+			t.Errorf("%+v: %q decodes to %q", tt.tables, tt.text, got)
+		}
+	}
+}
+
+func TestBestNationalTables(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		text  string
+		want  NationalTables
+		parts int
+	}{
+		{"default alphabet", "Hello", NationalTables{}, 1},
+		{"single shift is enough", "şa", NationalTables{Single: LanguageTurkish}, 1},
+		{"locking shift is shorter", strings.Repeat("ş", 100), NationalTables{Locking: LanguageTurkish}, 1},
+		// the single shift table fits in two parts only when the concatenation IE is left out
+		{"concatenation IE counted", "ş" + strings.Repeat("a", 297), NationalTables{Locking: LanguageTurkish}, 2},
+	} {
+		got, ok := BestNationalTables(tt.text, LanguageTurkish)
+		if !ok || got != tt.want {
+			t.Errorf("%s: got %+v %t, want %+v", tt.name, got, ok, tt.want)
+			continue
+		}
+		if n := got.Parts(tt.text); n != tt.parts {
+			t.Errorf("%s: %d parts, want %d", tt.name, n, tt.parts)
+		}
+	}
+	if _, ok := BestNationalTables("Ж", LanguageTurkish); ok {
+		t.Error("Cyrillic is covered by no table")
+	}
+}
@@ -0,0 +1,80 @@
+package coding
+
+// cr pads the last octet when it has exactly 7 spare bits, see 3GPP TS 23.038, section 6.1.2.3.1
+const cr byte = 0x0D
+
+// Septet limits of a single 140 octet user data
+const (
+	MaxOctets  = 140
+	MaxSeptets = 160
+)
+
+// FillBits returns the number of bits that align the first septet after a UDH of udhLen octets
+func FillBits(udhLen int) int {
+	return (7 - udhLen*8%7) % 7
+}
+
+// GSM7Septets returns how many septets fit in a short message next to a UDH of udhLen octets,
+// 160 without UDH and 153 with a concatenation UDH
+func GSM7Septets(udhLen int) int {
+	return ((MaxOctets-udhLen)*8 - FillBits(udhLen)) / 7
+}
+
+// PackGSM7 packs septets into octets, 8 characters per 7 octets. The result starts with
+// the fill bits for a UDH of udhLen octets, which itself is not included.
+func PackGSM7(septets []byte, udhLen int) []byte {
+	fill := FillBits(udhLen)
+	bits := fill + 7*len(septets)
+	if n := len(septets); n > 0 && septets[n-1] == cr && bits%8 == 0 {
+		// a trailing CR on an octet boundary is doubled, so it isn't taken for padding
+		septets = append(septets[:n:n], cr)
+		bits += 7
+	}
+	packed := make([]byte, (bits+7)/8)
+	for i, s := range septets {
+		packBits(packed, fill+7*i, s)
+	}
+	if bits%8 == 1 {
+		packBits(packed, bits, cr)
+	}
+	return packed
+}
+
+// UnpackGSM7 is the inverse of PackGSM7, packed must not contain the UDH
+func UnpackGSM7(packed []byte, udhLen int) []byte {
+	fill := FillBits(udhLen)
+	bits := len(packed)*8 - fill
+	if bits < 0 {
+		return nil
+	}
+	septets := make([]byte, bits/7)
+	for i := range septets {
+		bit := fill + 7*i
+		idx, shift := bit/8, bit%8
+		s := packed[idx] >> shift
+		if shift > 1 {
+			s |= packed[idx+1] << (8 - shift)
+		}
+		septets[i] = s & 0x7F
+	}
+	n := len(septets)
+	switch {
+	case n > 0 && bits%7 == 0 && septets[n-1] == cr:
+		// 7 spare bits padded with CR
+	case n > 1 && bits%7 == 1 && septets[n-1] == cr && septets[n-2] == cr:
+		// a trailing CR doubled on an octet boundary, a literal CR CR there reads as one CR,
+		// which the spec takes for the same
+	default:
+		return septets
+	}
+	return septets[:n-1]
+}
+
+func packBits(packed []byte, bit int, s byte) {
+	s &= 0x7F
+	idx, shift := bit/8, bit%8
+	packed[idx] |= s << shift
+	if shift > 1 {
+		packed[idx+1] |= s >> (8 - shift)
+	}
+}
@@ -0,0 +1,21 @@
+package coding
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPackGSM7RoundTrip(t *testing.T) {
+	for _, udhLen := range []int{0, 6, 7} {
+		for n := 1; n <= 16; n++ {
+			text := bytes.Repeat([]byte("a"), n)
+			withCR := append(bytes.Repeat([]byte("a"), n-1), cr)
+			for _, septets := range [][]byte{text, withCR} {
+				packed := PackGSM7(septets, udhLen)
+				if got := UnpackGSM7(packed, udhLen); !bytes.Equal(got, septets) {
+					t.Errorf("udh %d: %q packed to %x unpacks to %q", udhLen, septets, packed, got)
+				}
+			}
+		}
+	}
+}
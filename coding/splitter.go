@@ -37,7 +37,9 @@ func (fn Splitter) Len(input string) (n int) {
 	return n / 8
 }
 
-// 160/153, 140/134, 70/67
+// Split cuts input into segments of limit octets of user data next to a UDH of udhLength octets.
+// For GSM7 the limit is the packed size, 140, whether short_message is sent packed or not,
+// which yields 160/153 septets, see SplitGSM7. Others: 140/134 octets, 70/67 UCS2 characters.
 func (fn Splitter) Split(input string, limit int, udhLength int) (segments []string) {
 	limit *= 8
 	udhLength *= 8
@@ -62,3 +64,9 @@ func (fn Splitter) Split(input string, limit int, udhLength int) (segments []str
 
 	return segments
 }
+
+// SplitGSM7 cuts GSM7 text into segments of at most GSM7Septets(udhLength) septets,
+// an escaped character is never split from its escape.
+func SplitGSM7(input string, udhLength int) []string {
+	return _7BitSplitter.Split(input, MaxOctets, udhLength)
+}
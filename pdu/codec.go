@@ -0,0 +1,474 @@
+package pdu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+// Appender is implemented by PDU types that encode themselves without reflection
+type Appender interface {
+	AppendTo(buf []byte) ([]byte, error)
+}
+
+var bufPool = sync.Pool{
+	New: func() interface{} {
+		buf := make([]byte, 0, 512)
+		return &buf
+	},
+}
+
+// AppendPDU appends the encoded packet to buf, falling back to reflection for foreign types
+func AppendPDU(buf []byte, packet interface{}) ([]byte, *PDUError) {
+	p, ok := packet.(Appender)
+	if !ok {
+		var b bytes.Buffer
+		if _, perr := marshalReflect(&b, packet); perr != nil {
+			return buf, perr
+		}
+		return append(buf, b.Bytes()...), nil
+	}
+
+	start := len(buf)
+	out, err := p.AppendTo(buf)
+	if err != nil {
+		return buf, &PDUError{
+			Err: err,
+		}
+	}
+	if int32(binary.BigEndian.Uint32(out[start+12:])) <= 0 {
+		return buf, &PDUError{
+			CommandStatus: ESME_RUNKNOWNERR, // 255 - Unknown Error
+		}
+	}
+	return out, nil
+}
+
+// WritePDU encodes the packet and writes it with a single Write, without the hex dump of MarshalPDU
+func WritePDU(w io.Writer, packet interface{}) *PDUError {
+	bp := bufPool.Get().(*[]byte)
+	defer bufPool.Put(bp)
+
+	buf, perr := AppendPDU((*bp)[:0], packet)
+	*bp = buf
+	if perr != nil {
+		return perr
+	}
+	if _, err := w.Write(buf); err != nil {
+		return &PDUError{
+			Err: err,
+		}
+	}
+	return nil
+}
+
+// DecodePDU decodes a frame returned by ReadFrame. A malformed UDH is reported with
+// ESME_RINVMSGLEN together with the partly decoded packet.
+func DecodePDU(frame []byte) (interface{}, *Header, *PDUError) {
+	header := new(Header)
+	d := decoder{data: frame}
+	d.readHeader(header)
+	if d.err != nil {
+		return nil, header, &PDUError{
+			Err:           d.err,
+			CommandStatus: ESME_RINVCMDLEN, // 2 - Invalid Command Length
+		}
+	}
+
+	fn, ok := constructors[header.CommandID]
+	if !ok {
+		return nil, header, &PDUError{
+			CommandStatus: ESME_RINVCMDID, // 3 - Invalid Command ID
+		}
+	}
+
+	pdu := fn()
+	if err := pdu.UnmarshalBinary(frame); err != nil {
+		var udhErr *UDHError
+		if errors.As(err, &udhErr) {
+			// the frame is sound, the packet is returned so that it can be answered
+			return pdu, header, &PDUError{
+				Err:           err,
+				CommandStatus: udhErr.CommandStatus(), // 1 - Invalid Message Length
+			}
+		}
+		return nil, header, &PDUError{
+			Err:           err,
+			CommandStatus: ESME_RINVCMDLEN, // 2 - Invalid Command Length
+		}
+	}
+	return pdu, header, nil
+}
+
+type unmarshaler interface {
+	UnmarshalBinary(data []byte) error
+}
+
+var constructors = map[CommandID]func() unmarshaler{
+	AlertNotificationID:     func() unmarshaler { return new(AlertNotification) },
+	GenericNACKID:           func() unmarshaler { return new(GenericNACK) },
+	OutbindID:               func() unmarshaler { return new(Outbind) },
+	BindReceiverID:          func() unmarshaler { return new(BindReceiver) },
+	BindReceiverRespID:      func() unmarshaler { return new(BindReceiverResp) },
+	BindTransceiverID:       func() unmarshaler { return new(BindTransceiver) },
+	BindTransceiverRespID:   func() unmarshaler { return new(BindTransceiverResp) },
+	BindTransmitterID:       func() unmarshaler { return new(BindTransmitter) },
+	BindTransmitterRespID:   func() unmarshaler { return new(BindTransmitterResp) },
+	BroadcastSMID:           func() unmarshaler { return new(BroadcastSM) },
+	BroadcastSMRespID:       func() unmarshaler { return new(BroadcastSMResp) },
+	CancelBroadcastSMID:     func() unmarshaler { return new(CancelBroadcastSM) },
+	CancelBroadcastSMRespID: func() unmarshaler { return new(CancelBroadcastSMResp) },
+	CancelSMID:              func() unmarshaler { return new(CancelSM) },
+	CancelSMRespID:          func() unmarshaler { return new(CancelSMResp) },
+	DataSMID:                func() unmarshaler { return new(DataSM) },
+	DataSMRespID:            func() unmarshaler { return new(DataSMResp) },
+	DeliverSMID:             func() unmarshaler { return new(DeliverSM) },
+	DeliverSMRespID:         func() unmarshaler { return new(DeliverSMResp) },
+	EnquireLinkID:           func() unmarshaler { return new(EnquireLink) },
+	EnquireLinkRespID:       func() unmarshaler { return new(EnquireLinkResp) },
+	QueryBroadcastSMID:      func() unmarshaler { return new(QueryBroadcastSM) },
+	QueryBroadcastSMRespID:  func() unmarshaler { return new(QueryBroadcastSMResp) },
+	QuerySMID:               func() unmarshaler { return new(QuerySM) },
+	QuerySMRespID:           func() unmarshaler { return new(QuerySMResp) },
+	ReplaceSMID:             func() unmarshaler { return new(ReplaceSM) },
+	ReplaceSMRespID:         func() unmarshaler { return new(ReplaceSMResp) },
+	SubmitMultiID:           func() unmarshaler { return new(SubmitMulti) },
+	SubmitMultiRespID:       func() unmarshaler { return new(SubmitMultiResp) },
+	SubmitSMID:              func() unmarshaler { return new(SubmitSM) },
+	SubmitSMRespID:          func() unmarshaler { return new(SubmitSMResp) },
+	UnbindID:                func() unmarshaler { return new(Unbind) },
+	UnbindRespID:            func() unmarshaler { return new(UnbindResp) },
+}
+
+// appendHeader writes the header with a zero command_length, see finishPDU
+func appendHeader(buf []byte, h *Header, id CommandID) ([]byte, int) {
+	h.CommandID = id
+	start := len(buf)
+	buf = binary.BigEndian.AppendUint32(buf, 0)
+	buf = binary.BigEndian.AppendUint32(buf, uint32(id))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(h.CommandStatus))
+	buf = binary.BigEndian.AppendUint32(buf, uint32(h.Sequence))
+	return buf, start
+}
+
+// finishPDU patches command_length of the PDU starting at start
+func finishPDU(buf []byte, start int) []byte {
+	binary.BigEndian.PutUint32(buf[start:], uint32(len(buf)-start))
+	return buf
+}
+
+func appendCString(buf []byte, value string) []byte {
+	buf = append(buf, value...)
+	return append(buf, 0)
+}
+
+func appendBool(buf []byte, v bool) []byte {
+	return append(buf, getBool(v))
+}
+
+// decoder reads PDU fields from a bounded frame, the first error sticks
+type decoder struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (d *decoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.pos
+}
+
+func (d *decoder) readByte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if d.pos >= len(d.data) {
+		d.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	c := d.data[d.pos]
+	d.pos++
+	return c
+}
+
+func (d *decoder) readBool() bool {
+	return d.readByte() == 1
+}
+
+func (d *decoder) readUint16() uint16 {
+	if d.err != nil {
+		return 0
+	}
+	if d.remaining() < 2 {
+		d.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	v := binary.BigEndian.Uint16(d.data[d.pos:])
+	d.pos += 2
+	return v
+}
+
+func (d *decoder) readUint32() uint32 {
+	if d.err != nil {
+		return 0
+	}
+	if d.remaining() < 4 {
+		d.fail(io.ErrUnexpectedEOF)
+		return 0
+	}
+	v := binary.BigEndian.Uint32(d.data[d.pos:])
+	d.pos += 4
+	return v
+}
+
+func (d *decoder) readCString() string {
+	if d.err != nil {
+		return ""
+	}
+	i := bytes.IndexByte(d.data[d.pos:], 0)
+	if i < 0 {
+		d.fail(io.ErrUnexpectedEOF)
+		return ""
+	}
+	value := string(d.data[d.pos : d.pos+i])
+	d.pos += i + 1
+	return value
+}
+
+// readBytes returns a copy, so the frame may be reused by the caller
+func (d *decoder) readBytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if d.remaining() < n {
+		d.fail(io.ErrUnexpectedEOF)
+		return nil
+	}
+	value := make([]byte, n)
+	copy(value, d.data[d.pos:])
+	d.pos += n
+	return value
+}
+
+// readHeader reports whether the body follows, responses with an error status carry none
+func (d *decoder) readHeader(h *Header) bool {
+	h.CommandLength = d.readUint32()
+	h.CommandID = CommandID(d.readUint32())
+	h.CommandStatus = CommandStatus(d.readUint32())
+	h.Sequence = int32(d.readUint32())
+	if d.err == nil && int(h.CommandLength) != len(d.data) {
+		d.fail(ErrInvalidCommandLength)
+	}
+	return d.err == nil && h.CommandStatus == ESME_ROK
+}
+
+// finish checks that the body was consumed exactly
+func (d *decoder) finish(h *Header) error {
+	if d.err == nil && h.CommandStatus == ESME_ROK && d.pos != len(d.data) {
+		d.fail(ErrInvalidCommandLength)
+	}
+	return d.err
+}
+
+func (p SrcAddress) appendTo(buf []byte) []byte {
+	buf = append(buf, p.TON, p.NPI)
+	return appendCString(buf, p.Source)
+}
+
+func (p *SrcAddress) decode(d *decoder) {
+	p.TON = d.readByte()
+	p.NPI = d.readByte()
+	p.Source = d.readCString()
+}
+
+func (p DstAddress) appendTo(buf []byte) []byte {
+	buf = append(buf, p.TON, p.NPI)
+	return appendCString(buf, p.Dest)
+}
+
+func (p *DstAddress) decode(d *decoder) {
+	p.TON = d.readByte()
+	p.NPI = d.readByte()
+	p.Dest = d.readCString()
+}
+
+func (p DestinationAddresses) appendTo(buf []byte) ([]byte, error) {
+	length := len(p.Addresses) + len(p.DistributionList)
+	if length > 0xFF {
+		return buf, ErrInvalidDestCount
+	}
+	buf = append(buf, byte(length))
+	for _, address := range p.Addresses {
+		buf = append(buf, 1)
+		buf = address.appendTo(buf)
+	}
+	for _, distribution := range p.DistributionList {
+		buf = append(buf, 2)
+		buf = appendCString(buf, distribution)
+	}
+	return buf, nil
+}
+
+func (p *DestinationAddresses) decode(d *decoder) {
+	*p = DestinationAddresses{}
+	count := d.readByte()
+	for i := byte(0); i < count && d.err == nil; i++ {
+		switch d.readByte() {
+		case 1:
+			var address DstAddress
+			address.decode(d)
+			p.Addresses = append(p.Addresses, address)
+		case 2:
+			p.DistributionList = append(p.DistributionList, d.readCString())
+		default:
+			d.fail(ErrInvalidDestFlag)
+		}
+	}
+}
+
+func (p UnsuccessfulRecords) appendTo(buf []byte) ([]byte, error) {
+	if len(p) > 0xFF {
+		return buf, ErrItemTooMany
+	}
+	buf = append(buf, byte(len(p)))
+	for _, item := range p {
+		buf = item.DestAddr.appendTo(buf)
+		buf = binary.BigEndian.AppendUint32(buf, uint32(item.ErrorStatusCode))
+	}
+	return buf, nil
+}
+
+func (p *UnsuccessfulRecords) decode(d *decoder) {
+	count := d.readByte()
+	items := make(UnsuccessfulRecords, 0, count)
+	for i := byte(0); i < count && d.err == nil; i++ {
+		var item UnsuccessfulRecord
+		item.DestAddr.decode(d)
+		item.ErrorStatusCode = CommandStatus(d.readUint32())
+		items = append(items, item)
+	}
+	*p = items
+}
+
+func (t Tags) appendTo(buf []byte) ([]byte, error) {
+	if len(t) == 0 {
+		return buf, nil
+	}
+	keys := make([]uint16, 0, len(t))
+	for tag := range t {
+		keys = append(keys, tag)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, tag := range keys {
+		data := t[tag]
+		length := len(data)
+		if length == 0 {
+			continue
+		} else if length >= 0xFFFF {
+			return buf, ErrInvalidTagLength
+		}
+		buf = binary.BigEndian.AppendUint16(buf, tag)
+		buf = binary.BigEndian.AppendUint16(buf, uint16(length))
+		buf = append(buf, data...)
+	}
+	return buf, nil
+}
+
+// decode reads TLVs up to the end of the frame
+func (t *Tags) decode(d *decoder) {
+	var tags Tags
+	for d.err == nil && d.remaining() > 0 {
+		tag := d.readUint16()
+		data := d.readBytes(int(d.readUint16()))
+		if d.err == nil {
+			if tags == nil {
+				tags = make(Tags)
+			}
+			tags[tag] = data
+		}
+	}
+	if len(tags) > 0 {
+		*t = tags
+	}
+}
+
+func (h UserDataHeader) appendTo(buf []byte) ([]byte, error) {
+	if h == nil {
+		return buf, nil
+	}
+	start := len(buf)
+	buf = append(buf, 0)
+	for _, ie := range h {
+		if len(ie.Data) > 0xFF {
+			return buf[:start], ErrDataTooLarge
+		}
+		buf = append(buf, ie.ID, byte(len(ie.Data)))
+		buf = append(buf, ie.Data...)
+	}
+	buf[start] = byte(len(buf) - start - 1)
+	return buf, nil
+}
+
+// appendTo writes [data_coding] sm_default_msg_id sm_length short_message,
+// replace_sm has no data_coding and udhi forces a (possibly empty) UDH in front of a non-empty message.
+func (p *ShortMessage) appendTo(buf []byte, dataCoding bool, udhi bool) ([]byte, error) {
+	if dataCoding {
+		buf = append(buf, byte(p.DataCoding))
+	}
+	buf = append(buf, p.DefaultMessageID)
+	start := len(buf)
+	buf = append(buf, 0)
+
+	var err error
+	if p.UDHeader == nil && udhi && len(p.Message) > 0 {
+		buf = append(buf, 0)
+	} else if buf, err = p.UDHeader.appendTo(buf); err != nil {
+		return buf, err
+	}
+	buf = append(buf, p.Message...)
+
+	length := len(buf) - start - 1
+	if length > 0xFF {
+		return buf, ErrDataTooLarge
+	}
+	buf[start] = byte(length)
+	return buf, nil
+}
+
+func (p *ShortMessage) decode(d *decoder, dataCoding bool, udhi bool) {
+	if dataCoding {
+		p.DataCoding = coding.DataCoding(d.readByte())
+	} else {
+		p.DataCoding = coding.NoCoding
+	}
+	p.DefaultMessageID = d.readByte()
+	data := d.readBytes(int(d.readByte()))
+	if d.err != nil {
+		return
+	}
+
+	p.Message = data
+	if !udhi {
+		return
+	}
+	if len(data) == 0 {
+		p.UDHeader = nil // the UDH travels in message_payload, if at all
+		return
+	}
+	h, length, err := DecodeUserDataHeader(data)
+	if err != nil {
+		d.fail(err)
+		return
+	}
+	p.UDHeader, p.Message = h, data[length:]
+}
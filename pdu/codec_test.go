@@ -0,0 +1,167 @@
+package pdu
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+// codecSamples holds one populated packet of every PDU type
+func codecSamples() []interface{} {
+	src := SrcAddress{TON: 5, NPI: 0, Source: "sender"}
+	dst := DstAddress{TON: 1, NPI: 1, Dest: "79001234567"}
+	tags := func() Tags {
+		var t Tags
+		t.SetUserMessageReference(7)
+		t.SetReceiptedMessageID("abc")
+		return t
+	}
+	message := ShortMessage{DataCoding: coding.UCS2Coding, Message: []byte{0, 'h', 0, 'i'}}
+	udhMessage := ShortMessage{
+		DataCoding: coding.OctetCoding4,
+		UDHeader:   UserDataHeader{{ID: IEPorts16, Data: []byte{0x0B, 0x84, 0x23, 0xF0}}, {ID: 0x99, Data: []byte{1}}},
+		Message:    []byte{1, 2, 3},
+	}
+	esm := ESMClass{MessageMode: ModeStoreAndForward, MessageType: TypeMCDeliveryReceipt}
+	rd := RegisteredDelivery{MCDeliveryReceipt: ReceiptAlways, IntermediateNotification: true}
+	h := func(seq int32) Header { return Header{Sequence: seq} }
+
+	return []interface{}{
+		&BindTransmitter{Header: h(1), SystemID: "id", Password: "pw", SystemType: "t", Version: SMPPVersion34, TON: 1, NPI: 1, AddrRange: "^7"},
+		&BindTransmitterResp{Header: h(1), SystemID: "mc", Tags: tags()},
+		&BindReceiver{Header: h(2), SystemID: "id", Password: "pw", Version: SMPPVersion50},
+		&BindReceiverResp{Header: h(2), SystemID: "mc"},
+		&BindTransceiver{Header: h(3), SystemID: "id", Password: "pw", Version: SMPPVersion34},
+		&BindTransceiverResp{Header: h(3), SystemID: "mc", Tags: tags()},
+		&Outbind{Header: h(4), SystemID: "id", Password: "pw"},
+		&EnquireLink{Header: h(5)},
+		&EnquireLinkResp{Header: h(5)},
+		&Unbind{Header: h(6)},
+		&UnbindResp{Header: h(6)},
+		&GenericNACK{Header: Header{Sequence: 7, CommandStatus: ESME_RINVCMDID}},
+		&SubmitSM{Header: h(8), ServiceType: "CMT", SrcAddress: src, DstAddress: dst, ESMClass: esm, ProtocolID: 1, PriorityFlag: 2,
+			ScheduleDeliveryTime: "000001000000000R", RegisteredDelivery: rd, ReplaceIfPresent: true, ShortMessage: message, Tags: tags()},
+		&SubmitSM{Header: h(9), SrcAddress: src, DstAddress: dst, ESMClass: ESMClass{UDHIndicator: true}, ShortMessage: udhMessage},
+		&SubmitSMResp{Header: h(8), MessageID: "m1"},
+		&SubmitSMResp{Header: Header{Sequence: 9, CommandStatus: ESME_RTHROTTLED}},
+		&SubmitMulti{Header: h(10), SourceAddr: src, DestAddrList: DestinationAddresses{Addresses: []DstAddress{dst, dst}, DistributionList: []string{"list"}},
+			ESMClass: esm, RegisteredDelivery: rd, Message: message, Tags: tags()},
+		&SubmitMultiResp{Header: h(10), MessageID: "m2", UnsuccessfulSMEs: UnsuccessfulRecords{{DestAddr: dst, ErrorStatusCode: ESME_RINVDSTADR}}, Tags: tags()},
+		&DeliverSM{Header: h(11), SourceAddr: src, DestAddr: dst, ESMClass: esm, RegisteredDelivery: rd, Message: message, Tags: tags()},
+		&DeliverSM{Header: h(12), SourceAddr: src, DestAddr: dst, ESMClass: ESMClass{UDHIndicator: true}, Message: udhMessage},
+		&DeliverSMResp{Header: h(11), MessageID: "m3"},
+		&DataSM{Header: h(13), ServiceType: "WAP", SourceAddr: src, DestAddr: dst, ESMClass: esm, RegisteredDelivery: rd, DataCoding: coding.OctetCoding, Tags: tags()},
+		&DataSMResp{Header: h(13), MessageID: "m4", Tags: tags()},
+		&QuerySM{Header: h(14), MessageID: "m1", SourceAddr: src},
+		&QuerySMResp{Header: h(14), MessageID: "m1", FinalDate: "260118120000000+", MessageState: StateUndeliverable, ErrorCode: 5},
+		&ReplaceSM{Header: h(15), MessageID: "m1", SourceAddr: src, ValidityPeriod: "000002000000000R", RegisteredDelivery: rd,
+			Message: ShortMessage{Message: []byte("new")}, Tags: tags()},
+		&ReplaceSMResp{Header: h(15)},
+		&CancelSM{Header: h(16), ServiceType: "CMT", MessageID: "m1", SourceAddr: src, DestAddr: dst},
+		&CancelSMResp{Header: h(16)},
+		&AlertNotification{Header: h(17), SourceAddr: src, ESMEAddr: dst, Tags: tags()},
+		&BroadcastSM{Header: h(18), ServiceType: "CBS", SourceAddr: src, MessageID: "b1", PriorityFlag: 1, ValidityPeriod: "000001000000000R",
+			ReplaceIfPresent: true, DataCoding: coding.GSM7BitCoding, DefaultMessageID: 3, Tags: tags()},
+		&BroadcastSMResp{Header: h(18), MessageID: "b1", Tags: tags()},
+		&QueryBroadcastSM{Header: h(19), MessageID: "b1", SourceAddr: src, Tags: tags()},
+		&QueryBroadcastSMResp{Header: h(19), MessageID: "b1", Tags: tags()},
+		&CancelBroadcastSM{Header: h(20), ServiceType: "CBS", MessageID: "b1", SourceAddr: src, Tags: tags()},
+		&CancelBroadcastSMResp{Header: h(20)},
+	}
+}
+
+// reflectDiverges holds the encoding of the samples the reflective path gets wrong:
+// it skips the uint32 ErrorCode of query_sm_resp instead of writing the error_code octet
+var reflectDiverges = map[reflect.Type][]byte{
+	reflect.TypeOf(&QuerySMResp{}): append([]byte{
+		0, 0, 0, 0x26, 0x80, 0, 0, 0x03, 0, 0, 0, 0, 0, 0, 0, 14, 'm', '1', 0,
+		'2', '6', '0', '1', '1', '8', '1', '2', '0', '0', '0', '0', '0', '0', '0', '+', 0},
+		byte(StateUndeliverable), 5),
+}
+
+// reflectEncode encodes packet with the reflective path the codec replaces
+func reflectEncode(t testing.TB, packet interface{}) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, perr := marshalReflect(&buf, packet); perr != nil {
+		t.Fatalf("%T: marshalReflect: %v", packet, perr)
+	}
+	return buf.Bytes()
+}
+
+func TestCodecMatchesReflection(t *testing.T) {
+	for i, packet := range codecSamples() {
+		name := fmt.Sprintf("%d/%T", i, packet)
+		t.Run(name, func(t *testing.T) {
+			fast := encode(t, packet)
+			if want, ok := reflectDiverges[reflect.TypeOf(packet)]; ok {
+				if !bytes.Equal(fast, want) {
+					t.Fatalf("AppendTo %x, want %x", fast, want)
+				}
+				return
+			}
+			if slow := reflectEncode(t, packet); !bytes.Equal(fast, slow) {
+				t.Fatalf("AppendTo %x\nreflect  %x", fast, slow)
+			}
+
+			decoded, _, perr := DecodePDU(fast)
+			if perr != nil {
+				t.Fatalf("DecodePDU: %v", perr)
+			}
+			reflected := reflect.New(reflect.TypeOf(packet).Elem()).Interface()
+			if _, err := UnmarshalPDU(bytes.NewReader(fast), reflected); err != nil {
+				t.Fatalf("UnmarshalPDU: %v", err)
+			}
+			if got := encode(t, decoded); !bytes.Equal(got, fast) {
+				t.Errorf("UnmarshalBinary round trip %x, want %x", got, fast)
+			}
+			if got := reflectEncode(t, reflected); !bytes.Equal(got, fast) {
+				t.Errorf("UnmarshalPDU round trip %x, want %x", got, fast)
+			}
+		})
+	}
+}
+
+func benchmarkSubmitSM() *SubmitSM {
+	p := testSubmitSM()
+	p.ShortMessage.Message = bytes.Repeat([]byte("a"), 140)
+	return p
+}
+
+func BenchmarkAppendPDU(b *testing.B) {
+	p := benchmarkSubmitSM()
+	buf := make([]byte, 0, 512)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf, _ = AppendPDU(buf[:0], p)
+	}
+}
+
+func BenchmarkMarshalReflect(b *testing.B) {
+	p := benchmarkSubmitSM()
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		_, _ = marshalReflect(&buf, p)
+	}
+}
+
+func BenchmarkDecodePDU(b *testing.B) {
+	frame := encode(b, benchmarkSubmitSM())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = DecodePDU(frame)
+	}
+}
+
+func BenchmarkUnmarshalPDU(b *testing.B) {
+	frame := encode(b, benchmarkSubmitSM())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = UnmarshalPDU(bytes.NewReader(frame), new(SubmitSM))
+	}
+}
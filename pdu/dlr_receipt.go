@@ -3,7 +3,11 @@ package pdu
 import (
 	"fmt"
 	"regexp"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/goldsheva/smpp-lib/coding"
 )
 
 type DeliveryReceipt struct {
@@ -31,34 +35,239 @@ func (dlr *DeliveryReceipt) GenerateDLRString() string {
 		dlr.ID, dlr.Sub, dlr.Dlvrd, dlr.SubmitDate, dlr.DoneDate, dlr.Status, dlr.Error, dlr.Text)
 }
 
-// ParseDLR parse short_message to struct DeliveryReceipt.
+// ParseDLR parse short_message to struct DeliveryReceipt, see ParseReceipt for the accepted variants.
 func ParseDLR(dlrMessage string) (*DeliveryReceipt, error) {
-	re := regexp.MustCompile(`id:(\S+) sub:(\S+) dlvrd:(\S+) submit date:(\S+) done date:(\S+) stat:(\S+) err:(\S+)(?: text:(.*))?`)
+	fields, err := scanReceipt(dlrMessage)
+	if err != nil {
+		return nil, err
+	}
+	return &DeliveryReceipt{
+		ID:         fields["id"],
+		Sub:        fields["sub"],
+		Dlvrd:      fields["dlvrd"],
+		SubmitDate: fields["submit date"],
+		DoneDate:   fields["done date"],
+		Status:     fields["stat"],
+		Error:      fields["err"],
+		Text:       fields["text"],
+	}, nil
+}
+
+// Receipt is a delivery receipt with typed fields
+type Receipt struct {
+	ID         string       `json:"id"`
+	Sub        int          `json:"sub"`
+	Dlvrd      int          `json:"dlvrd"`
+	SubmitDate time.Time    `json:"submit_date"`
+	DoneDate   time.Time    `json:"done_date"`
+	State      MessageState `json:"state"` // StateUnknown when stat is missing or not recognised
+	Status     string       `json:"stat"`  // as sent, e.g. DELIVRD
+	Error      int          `json:"err"`   // zero when err is not a number
+	Text       string       `json:"text"`
+
+	// NetworkError is taken from the network_error_code TLV
+	NetworkError *NetworkErrorCode `json:"network_error,omitempty"`
+}
 
-	matches := re.FindStringSubmatch(dlrMessage)
-	if matches == nil {
-		return nil, fmt.Errorf("invalid DLR format")
+// receiptKey matches the field names of a receipt, vendors differ in case and separators
+var receiptKey = regexp.MustCompile(`(?i)\b(id|sub|dlvrd|submit[ _]?date|done[ _]?date|stat|err|text)\s*:`)
+
+// receiptKeyName folds the date names to "submit date" and "done date"
+var receiptKeyName = strings.NewReplacer("submit_date", "submit date", "submitdate", "submit date", "done_date", "done date", "donedate", "done date")
+
+// scanReceipt splits a receipt into its fields keyed by lower case name. Fields may come in
+// any order, a field seen twice ends the previous value, so text may hold "id:" after the id.
+func scanReceipt(message string) (map[string]string, error) {
+	matches := receiptKey.FindAllStringSubmatchIndex(message, -1)
+	fields := make(map[string]string, len(matches))
+	var name string
+	var start int
+	for _, m := range matches {
+		key := receiptKeyName.Replace(strings.ToLower(message[m[2]:m[3]]))
+		if _, seen := fields[key]; seen || key == name {
+			continue
+		}
+		if name != "" {
+			fields[name] = strings.TrimSpace(message[start:m[0]])
+		}
+		name, start = key, m[1]
+	}
+	if name != "" {
+		fields[name] = strings.TrimSpace(message[start:])
 	}
+	if fields["id"] == "" {
+		return nil, ErrInvalidReceipt
+	}
+	return fields, nil
+}
 
-	var parsedText = ""
-	if len(matches) > 8 {
-		parsedText = matches[8]
+// ParseReceipt parses the receipt text of a deliver_sm. It accepts any case of the field names,
+// missing sub/dlvrd, dates with or without seconds and any order of the fields. An unknown stat
+// or an err that is no number doesn't fail the receipt. Dates are read in loc, nil means UTC.
+func ParseReceipt(message string, loc *time.Location) (*Receipt, error) {
+	fields, err := scanReceipt(message)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		loc = time.UTC
 	}
 
-	dlr := &DeliveryReceipt{
-		ID:         matches[1],
-		Sub:        matches[2],
-		Dlvrd:      matches[3],
-		SubmitDate: matches[4],
-		DoneDate:   matches[5],
-		Status:     matches[6],
-		Error:      matches[7],
-		Text:       parsedText,
+	r := &Receipt{ID: fields["id"], State: StateUnknown, Status: fields["stat"], Text: fields["text"]}
+	r.Sub, _ = strconv.Atoi(fields["sub"])
+	r.Dlvrd, _ = strconv.Atoi(fields["dlvrd"])
+	if r.SubmitDate, err = parseReceiptDate(fields["submit date"], loc); err != nil {
+		return nil, err
 	}
+	if r.DoneDate, err = parseReceiptDate(fields["done date"], loc); err != nil {
+		return nil, err
+	}
+	if state, ok := ParseMessageState(r.Status); ok {
+		r.State = state
+	}
+	r.Error = parseReceiptError(fields["err"])
+	return r, nil
+}
 
-	if dlr.ID == "" {
-		return nil, fmt.Errorf("invalid MessageID")
+// ParseDeliverSMReceipt parses the receipt of p, receipted_message_id, message_state and
+// network_error_code TLVs take precedence over the text. The text may be empty if the TLVs are set,
+// an empty short_message is read from message_payload.
+func ParseDeliverSMReceipt(p *DeliverSM, loc *time.Location) (*Receipt, error) {
+	text := p.Message.Decode()
+	if text == "" {
+		if payload, ok := p.Tags.MessagePayload(); ok {
+			text = (&ShortMessage{DataCoding: p.Message.DataCoding, Message: payload}).Decode()
+		}
+	}
+	r, err := ParseReceipt(text, loc)
+	if err != nil {
+		if _, ok := p.Tags.ReceiptedMessageID(); !ok {
+			return nil, err
+		}
+		r = &Receipt{State: StateUnknown}
+	}
+	if id, ok := p.Tags.ReceiptedMessageID(); ok && id != "" {
+		r.ID = id
+	}
+	if state, ok := p.Tags.MessageState(); ok {
+		r.State = state
 	}
+	if code, ok := p.Tags.NetworkErrorCode(); ok {
+		r.NetworkError = &code
+	}
+	return r, nil
+}
 
-	return dlr, nil
+// parseReceiptError reads a decimal err, or a hex one with or without 0x, e.g. 00B
+func parseReceiptError(value string) int {
+	if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") {
+		value = value[2:]
+	} else if code, err := strconv.ParseInt(value, 10, 32); err == nil {
+		return int(code)
+	}
+	if code, err := strconv.ParseInt(value, 16, 32); err == nil {
+		return int(code)
+	}
+	return 0
+}
+
+// parseReceiptDate accepts YYMMDDhhmm, YYMMDDhhmmss and YYYYMMDDhhmmss
+func parseReceiptDate(value string, loc *time.Location) (time.Time, error) {
+	var layout string
+	switch len(value) {
+	case 0:
+		return time.Time{}, nil
+	case 10:
+		layout = "0601021504"
+	case 12:
+		layout = "060102150405"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("%w: date %s", ErrInvalidReceipt, value)
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: date %s", ErrInvalidReceipt, value)
+	}
+	return t, nil
+}
+
+// ReceiptOptions completes a receipt built by NewDeliveryReceipt
+type ReceiptOptions struct {
+	SubmitDate time.Time
+	DoneDate   time.Time // zero means now
+	Error      int
+	// NetworkError adds the network_error_code TLV
+	NetworkError *NetworkErrorCode
+}
+
+// NewDeliveryReceipt builds the deliver_sm receipt of submit for an MC, addresses are swapped and
+// the text holds the first 20 characters of the message. It returns nil when the registered_delivery
+// of submit doesn't ask for a receipt in that state, a state that isn't final is sent as an
+// intermediate notification. The receipt is GSM7 text, characters outside the default alphabet
+// are substituted and a receipt beyond 160 septets goes into message_payload.
+func NewDeliveryReceipt(submit *SubmitSM, messageID string, state MessageState, opts ReceiptOptions) *DeliverSM {
+	if !submit.RegisteredDelivery.WantsReceiptFor(state) {
+		return nil
+	}
+	messageType := TypeMCDeliveryReceipt
+	if !state.IsFinal() {
+		messageType = TypeIntermediateNotification
+	}
+
+	if opts.DoneDate.IsZero() {
+		opts.DoneDate = time.Now()
+	}
+	text := submit.ShortMessage.Decode()
+	if text == "" {
+		if payload, ok := submit.Tags.MessagePayload(); ok {
+			text = (&ShortMessage{DataCoding: submit.ShortMessage.DataCoding, Message: payload}).Decode()
+		}
+	}
+	if runes := []rune(text); len(runes) > 20 {
+		text = string(runes[:20])
+	}
+	dlvrd := "000"
+	if state == StateDelivered {
+		dlvrd = "001"
+	}
+	receipt := &DeliveryReceipt{
+		ID:         messageID,
+		Sub:        "001",
+		Dlvrd:      dlvrd,
+		SubmitDate: formatReceiptDate(opts.SubmitDate),
+		DoneDate:   formatReceiptDate(opts.DoneDate),
+		Status:     state.DLRCode(),
+		Error:      fmt.Sprintf("%03d", opts.Error),
+		Text:       text,
+	}
+	message := coding.EncodeGSM7(coding.ReplaceSubstitutions(receipt.GenerateDLRString()))
+
+	p := &DeliverSM{
+		ServiceType: submit.ServiceType,
+		SourceAddr:  SrcAddress{TON: submit.DstAddress.TON, NPI: submit.DstAddress.NPI, Source: submit.DstAddress.Dest},
+		DestAddr:    DstAddress{TON: submit.SrcAddress.TON, NPI: submit.SrcAddress.NPI, Dest: submit.SrcAddress.Source},
+		ESMClass:    ESMClass{MessageType: messageType},
+		Message:     ShortMessage{DataCoding: coding.GSM7BitCoding},
+	}
+	if len(message) > coding.MaxSeptets {
+		p.Tags.SetMessagePayload(message)
+	} else {
+		p.Message.Message = message
+	}
+	p.Tags.SetReceiptedMessageID(messageID)
+	p.Tags.SetMessageState(state)
+	if opts.NetworkError != nil {
+		p.Tags.SetNetworkErrorCode(*opts.NetworkError)
+	}
+	return p
+}
+
+// formatReceiptDate formats YYMMDDhhmm, a zero time is left empty
+func formatReceiptDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("0601021504")
 }
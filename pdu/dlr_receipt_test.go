@@ -0,0 +1,130 @@
+package pdu
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+func TestNewDeliveryReceiptGSM7(t *testing.T) {
+	submit := testSubmitSM()
+	submit.RegisteredDelivery.MCDeliveryReceipt = ReceiptAlways
+
+	for _, tt := range []struct {
+		id, text string
+		payload  bool
+	}{
+		{"m1", "hello", false},
+		{"m2", "привет, как дела? 中文", false},
+		{strings.Repeat("9", 65), strings.Repeat("{", 20), true},
+	} {
+		submit.ShortMessage = ShortMessage{DataCoding: coding.UCS2Coding, Message: EncodeMessage(tt.text, coding.UCS2Coding)}
+		p := NewDeliveryReceipt(submit, tt.id, StateDelivered, ReceiptOptions{})
+		if p.Message.DataCoding != coding.GSM7BitCoding {
+			t.Errorf("%s: data_coding %v, want GSM7", tt.id, p.Message.DataCoding)
+		}
+		payload, ok := p.Tags.MessagePayload()
+		if ok != tt.payload || len(p.Message.Message) > coding.MaxSeptets || len(payload) > 0 && len(p.Message.Message) > 0 {
+			t.Errorf("%s: short_message %d octets, message_payload %d octets", tt.id, len(p.Message.Message), len(payload))
+		}
+
+		p.Header.Sequence = 1
+		decoded, _, perr := DecodePDU(encode(t, p))
+		if perr != nil {
+			t.Fatalf("%s: %v", tt.id, perr)
+		}
+		receipt := decoded.(*DeliverSM)
+		delete(receipt.Tags, TagReceiptedMessageID) // the ID is read from the text
+		r, err := ParseDeliverSMReceipt(receipt, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.id, err)
+		}
+		if r.ID != tt.id || r.State != StateDelivered || r.Text == "" {
+			t.Errorf("%s: got %+v", tt.id, r)
+		}
+	}
+}
+
+func TestParseReceipt(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, min, sec int) time.Time {
+		return time.Date(year, month, day, hour, min, sec, 0, time.UTC)
+	}
+	for _, tt := range []struct {
+		name    string
+		message string
+		want    Receipt
+	}{
+		{
+			"standard",
+			"id:m1 sub:001 dlvrd:001 submit date:2401021504 done date:2401021505 stat:DELIVRD err:000 text:hello",
+			Receipt{ID: "m1", Sub: 1, Dlvrd: 1, SubmitDate: date(2024, 1, 2, 15, 4, 0), DoneDate: date(2024, 1, 2, 15, 5, 0), State: StateDelivered, Status: "DELIVRD", Text: "hello"},
+		},
+		{
+			"upper case names without sub and dlvrd",
+			"Id:m2 Submit_Date:2401021504 DONE DATE:2401021505 Stat:UNDELIV Err:011",
+			Receipt{ID: "m2", SubmitDate: date(2024, 1, 2, 15, 4, 0), DoneDate: date(2024, 1, 2, 15, 5, 0), State: StateUndeliverable, Status: "UNDELIV", Error: 11},
+		},
+		{
+			"dates with seconds",
+			"id:m3 submit date:240102150405 done date:20240102150506 stat:EXPIRED",
+			Receipt{ID: "m3", SubmitDate: date(2024, 1, 2, 15, 4, 5), DoneDate: date(2024, 1, 2, 15, 5, 6), State: StateExpired, Status: "EXPIRED"},
+		},
+		{
+			"text before err",
+			"id:m4 stat:REJECTD text:stat:fake id:x err:0x1F",
+			Receipt{ID: "m4", State: StateRejected, Status: "REJECTD", Text: "stat:fake id:x", Error: 0x1F},
+		},
+		{
+			"unknown stat and hex err",
+			"id:m5 stat:ENROUTE_OK err:00B",
+			Receipt{ID: "m5", State: StateUnknown, Status: "ENROUTE_OK", Error: 0xB},
+		},
+		{
+			"stat with trailing junk and no number err",
+			"id:m6 stat:ENROUTE 1 err:n/a",
+			Receipt{ID: "m6", State: StateUnknown, Status: "ENROUTE 1"},
+		},
+	} {
+		r, err := ParseReceipt(tt.message, nil)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if *r != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.name, *r, tt.want)
+		}
+	}
+
+	for _, message := range []string{"sub:001 stat:DELIVRD", "id:m1 submit date:24010215"} {
+		if _, err := ParseReceipt(message, nil); !errors.Is(err, ErrInvalidReceipt) {
+			t.Errorf("%q: got %v, want ErrInvalidReceipt", message, err)
+		}
+	}
+}
+
+func TestParseDeliverSMReceiptTLVs(t *testing.T) {
+	p := &DeliverSM{}
+	p.Tags.SetMessagePayload([]byte("id:text-id stat:ENROUTE err:005"))
+	p.Tags.SetReceiptedMessageID("tlv-id")
+	p.Tags.SetMessageState(StateDelivered)
+	p.Tags.SetNetworkErrorCode(NetworkErrorCode{NetworkType: 3, ErrorCode: 0x0102})
+
+	r, err := ParseDeliverSMReceipt(p, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.ID != "tlv-id" || r.State != StateDelivered || r.Status != "ENROUTE" || r.Error != 5 || r.NetworkError == nil || *r.NetworkError != (NetworkErrorCode{NetworkType: 3, ErrorCode: 0x0102}) {
+		t.Errorf("got %+v", r)
+	}
+
+	// the TLVs make up for a text that is no receipt
+	p.Tags = nil
+	p.Message.Message = []byte("no receipt")
+	p.Tags.SetReceiptedMessageID("tlv-id")
+	if r, err = ParseDeliverSMReceipt(p, nil); err != nil || r.ID != "tlv-id" || r.State != StateUnknown {
+		t.Errorf("got %+v, %v", r, err)
+	}
+}
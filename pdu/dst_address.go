@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"bytes"
 	"encoding/binary"
-	"errors"
 	"fmt"
 	"io"
 	"strings"
@@ -68,7 +67,7 @@ func (p *DestinationAddresses) ReadFrom(r io.Reader) (n int64, err error) {
 	buf := bufio.NewReader(r)
 	count, err := buf.ReadByte()
 	if err != nil {
-		err = errors.New("InvalidCommandLength")
+		err = ErrInvalidCommandLength
 		return
 	}
 	*p = DestinationAddresses{}
@@ -86,11 +85,11 @@ func (p *DestinationAddresses) ReadFrom(r io.Reader) (n int64, err error) {
 				p.DistributionList = append(p.DistributionList, value)
 			}
 		default:
-			err = errors.New("InvalidDestFlag")
+			err = ErrInvalidDestFlag
 			return
 		}
 		if err != nil {
-			err = errors.New("InvalidCommandLength")
+			err = ErrInvalidCommandLength
 			return
 		}
 	}
@@ -101,7 +100,7 @@ func (p *DestinationAddresses) ReadFrom(r io.Reader) (n int64, err error) {
 func (p DestinationAddresses) WriteTo(w io.Writer) (n int64, err error) {
 	length := len(p.Addresses) + len(p.DistributionList)
 	if length > 0xFF {
-		err = errors.New("InvalidDestCount")
+		err = ErrInvalidDestCount
 		return
 	}
 	var buf bytes.Buffer
@@ -127,7 +126,7 @@ func (p *UnsuccessfulRecords) ReadFrom(r io.Reader) (n int64, err error) {
 	buf := bufio.NewReader(r)
 	count, err := buf.ReadByte()
 	if err != nil {
-		err = errors.New("InvalidCommandLength")
+		err = ErrInvalidCommandLength
 		return
 	}
 	items := UnsuccessfulRecords{}
@@ -138,7 +137,7 @@ func (p *UnsuccessfulRecords) ReadFrom(r io.Reader) (n int64, err error) {
 			err = binary.Read(buf, binary.BigEndian, &item.ErrorStatusCode)
 		}
 		if err != nil {
-			err = errors.New("InvalidCommandLength")
+			err = ErrInvalidCommandLength
 			return
 		}
 		items = append(items, item)
@@ -150,7 +149,7 @@ func (p *UnsuccessfulRecords) ReadFrom(r io.Reader) (n int64, err error) {
 // WriteTo ...
 func (p UnsuccessfulRecords) WriteTo(w io.Writer) (n int64, err error) {
 	if len(p) > 0xFF {
-		err = errors.New("ItemTooMany")
+		err = ErrItemTooMany
 		return
 	}
 	var buf bytes.Buffer
@@ -1,5 +1,35 @@
 package pdu
 
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidCommandLength = errors.New("InvalidCommandLength")
+	ErrInvalidDestFlag      = errors.New("InvalidDestFlag")
+	ErrInvalidDestCount     = errors.New("InvalidDestCount")
+	ErrItemTooMany          = errors.New("ItemTooMany")
+	ErrDataTooLarge         = errors.New("DataTooLarge")
+	ErrInvalidTagLength     = errors.New("InvalidTagLength")
+	ErrInvalidUDHLength     = errors.New("InvalidUDHLength")
+	ErrInvalidIELength      = errors.New("InvalidIELength")
+	ErrUnmarshalPDUFailed   = errors.New("UnmarshalPDUFailed")
+	ErrInvalidVendorTag     = errors.New("InvalidVendorTag")
+	ErrInvalidTagName       = errors.New("InvalidTagName")
+	ErrInvalidReceipt       = errors.New("InvalidReceipt")
+	ErrInvalidTimeFormat    = errors.New("InvalidTimeFormat")
+	ErrInvalidMessageState  = errors.New("InvalidMessageState")
+	ErrInvalidWAPPush       = errors.New("InvalidWAPPush")
+	ErrInvalidWBXML         = errors.New("InvalidWBXML")
+	ErrInvalidPortAddress   = errors.New("InvalidPortAddress")
+	ErrIncompleteMessage    = errors.New("IncompleteMessage")
+	ErrInvalidVCard         = errors.New("InvalidVCard")
+	ErrInvalidDataCoding    = errors.New("InvalidDataCoding")
+	ErrInvalidReference     = errors.New("InvalidReference")
+)
+
+// CommandStatus see SMPP v5, section 4.7.6 (116p)
 type CommandStatus uint32
 
 const (
@@ -103,3 +133,120 @@ var STATUS_DESCRIPTION = map[CommandStatus]string{
 	ESME_RDELIVERYFAILURE: "Delivery Failure (used data_sm_resp)",
 	ESME_RUNKNOWNERR:      "Unknown Error",
 }
+
+var statusNames = map[CommandStatus]string{
+	ESME_ROK:              "ESME_ROK",
+	ESME_RINVMSGLEN:       "ESME_RINVMSGLEN",
+	ESME_RINVCMDLEN:       "ESME_RINVCMDLEN",
+	ESME_RINVCMDID:        "ESME_RINVCMDID",
+	ESME_RINVBNDSTS:       "ESME_RINVBNDSTS",
+	ESME_RALYBND:          "ESME_RALYBND",
+	ESME_RINVPRTFLG:       "ESME_RINVPRTFLG",
+	ESME_RINVREGDLVFLG:    "ESME_RINVREGDLVFLG",
+	ESME_RSYSERR:          "ESME_RSYSERR",
+	ESME_RINVSRCADR:       "ESME_RINVSRCADR",
+	ESME_RINVDSTADR:       "ESME_RINVDSTADR",
+	ESME_RINVMSGID:        "ESME_RINVMSGID",
+	ESME_RBINDFAIL:        "ESME_RBINDFAIL",
+	ESME_RINVPASWD:        "ESME_RINVPASWD",
+	ESME_RINVSYSID:        "ESME_RINVSYSID",
+	ESME_RCANCELFAIL:      "ESME_RCANCELFAIL",
+	ESME_RREPLACEFAIL:     "ESME_RREPLACEFAIL",
+	ESME_RMSGQFUL:         "ESME_RMSGQFUL",
+	ESME_RINVSERTYP:       "ESME_RINVSERTYP",
+	ESME_RINVNUMDESTS:     "ESME_RINVNUMDESTS",
+	ESME_RINVDLNAME:       "ESME_RINVDLNAME",
+	ESME_RINVDESTFLAG:     "ESME_RINVDESTFLAG",
+	ESME_RINVSUBREP:       "ESME_RINVSUBREP",
+	ESME_RINVESMCLASS:     "ESME_RINVESMCLASS",
+	ESME_RCNTSUBDL:        "ESME_RCNTSUBDL",
+	ESME_RSUBMITFAIL:      "ESME_RSUBMITFAIL",
+	ESME_RINVSRCTON:       "ESME_RINVSRCTON",
+	ESME_RINVSRCNPI:       "ESME_RINVSRCNPI",
+	ESME_RINVDSTTON:       "ESME_RINVDSTTON",
+	ESME_RINVDSTNPI:       "ESME_RINVDSTNPI",
+	ESME_RINVSYSTYP:       "ESME_RINVSYSTYP",
+	ESME_RINVREPFLAG:      "ESME_RINVREPFLAG",
+	ESME_RINVNUMMSGS:      "ESME_RINVNUMMSGS",
+	ESME_RTHROTTLED:       "ESME_RTHROTTLED",
+	ESME_RINVSCHED:        "ESME_RINVSCHED",
+	ESME_RINVEXPIRY:       "ESME_RINVEXPIRY",
+	ESME_RINVDFTMSGID:     "ESME_RINVDFTMSGID",
+	ESME_RX_T_APPN:        "ESME_RX_T_APPN",
+	ESME_RX_P_APPN:        "ESME_RX_P_APPN",
+	ESME_RX_R_APPN:        "ESME_RX_R_APPN",
+	ESME_RQUERYFAIL:       "ESME_RQUERYFAIL",
+	ESME_RINVOPTPARSTREAM: "ESME_RINVOPTPARSTREAM",
+	ESME_ROPTPARNOTALLWD:  "ESME_ROPTPARNOTALLWD",
+	ESME_RINVPARLEN:       "ESME_RINVPARLEN",
+	ESME_RMISSINGOPTPARAM: "ESME_RMISSINGOPTPARAM",
+	ESME_RINVOPTPARAMVAL:  "ESME_RINVOPTPARAMVAL",
+	ESME_RDELIVERYFAILURE: "ESME_RDELIVERYFAILURE",
+	ESME_RUNKNOWNERR:      "ESME_RUNKNOWNERR",
+}
+
+// String returns the constant name, e.g. ESME_RINVPASWD
+func (s CommandStatus) String() string {
+	if name, ok := statusNames[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("%08X", uint32(s))
+}
+
+// Error returns the description from STATUS_DESCRIPTION
+func (s CommandStatus) Error() string {
+	if desc, ok := STATUS_DESCRIPTION[s]; ok {
+		return desc
+	}
+	return fmt.Sprintf("Command Status %08X", uint32(s))
+}
+
+// PDUError ...
+type PDUError struct {
+	CommandStatus CommandStatus
+	Err           error
+}
+
+// Error ...
+func (e *PDUError) Error() string {
+	switch {
+	case e.Err == nil:
+		return e.CommandStatus.Error()
+	case e.CommandStatus == ESME_ROK:
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.CommandStatus.Error(), e.Err.Error())
+}
+
+// Unwrap lets errors.Is match both the command status and the cause
+func (e *PDUError) Unwrap() []error {
+	errs := make([]error, 0, 2)
+	if e.CommandStatus != ESME_ROK {
+		errs = append(errs, e.CommandStatus)
+	}
+	if e.Err != nil {
+		errs = append(errs, e.Err)
+	}
+	return errs
+}
+
+// UDHError is returned for a malformed UDH, servers answer it with ESME_RINVMSGLEN
+type UDHError struct {
+	Offset int // of the UDHL or the IE within the short message
+	Err    error
+}
+
+// Error ...
+func (e *UDHError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Err.Error(), e.Offset)
+}
+
+// Unwrap ...
+func (e *UDHError) Unwrap() error {
+	return e.Err
+}
+
+// CommandStatus ...
+func (e *UDHError) CommandStatus() CommandStatus {
+	return ESME_RINVMSGLEN
+}
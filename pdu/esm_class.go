@@ -13,6 +13,39 @@ type ESMClass struct {
 	ReplyPath    bool // *_ ____ __
 }
 
+// Messaging modes of ESMClass.MessageMode
+const (
+	ModeDefault         byte = 0b00 // default MC mode, usually store and forward
+	ModeDatagram        byte = 0b01
+	ModeForward         byte = 0b10 // transaction mode
+	ModeStoreAndForward byte = 0b11
+)
+
+// Message types of ESMClass.MessageType
+const (
+	TypeDefault                  byte = 0b0000
+	TypeMCDeliveryReceipt        byte = 0b0001
+	TypeSMEDeliveryAck           byte = 0b0010
+	TypeSMEManualAck             byte = 0b0100
+	TypeConversationAbort        byte = 0b0110 // Korean CDMA
+	TypeIntermediateNotification byte = 0b1000
+)
+
+// IsDeliveryReceipt reports whether the deliver_sm carries an MC delivery receipt
+func (e ESMClass) IsDeliveryReceipt() bool {
+	return e.MessageType == TypeMCDeliveryReceipt
+}
+
+// IsIntermediateNotification ...
+func (e ESMClass) IsIntermediateNotification() bool {
+	return e.MessageType == TypeIntermediateNotification
+}
+
+// IsSMEAck reports whether the deliver_sm carries a delivery or manual acknowledgement
+func (e ESMClass) IsSMEAck() bool {
+	return e.MessageType == TypeSMEDeliveryAck || e.MessageType == TypeSMEManualAck
+}
+
 // ReadByte converts ESMClass to a byte
 func (e ESMClass) ReadByte() (c byte, err error) {
 	c |= e.MessageMode & 0b11
@@ -42,3 +75,12 @@ func (e ESMClass) MarshalJSON() (data []byte, err error) {
 	c, _ := e.ReadByte()
 	return json.Marshal(c)
 }
+
+// UnmarshalJSON unmarshals ESMClass from JSON
+func (e *ESMClass) UnmarshalJSON(data []byte) error {
+	var c byte
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+	return e.WriteByte(c)
+}
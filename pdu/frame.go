@@ -0,0 +1,37 @@
+package pdu
+
+import (
+	"bytes"
+	"io"
+)
+
+// ReadFrame reads exactly one PDU from r: the header and command_length-16 bytes of body.
+// The returned frame includes the header, so it can be passed to UnmarshalPDU as is.
+func ReadFrame(r io.Reader) (frame []byte, header *Header, err error) {
+	header = new(Header)
+
+	var head [16]byte
+	if _, err = io.ReadFull(r, head[:]); err != nil {
+		return
+	}
+	if err = ReadPDUHeader(bytes.NewReader(head[:]), header); err != nil {
+		return head[:], header, err
+	}
+
+	frame = make([]byte, header.CommandLength)
+	copy(frame, head[:])
+	_, err = io.ReadFull(r, frame[len(head):])
+	return
+}
+
+// countingReader counts the bytes read from the underlying reader
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (n int, err error) {
+	n, err = c.r.Read(p)
+	c.n += int64(n)
+	return
+}
@@ -0,0 +1,107 @@
+package pdu
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+func encode(t testing.TB, packet interface{}) []byte {
+	t.Helper()
+	data, perr := AppendPDU(nil, packet)
+	if perr != nil {
+		t.Fatalf("AppendPDU: %v", perr)
+	}
+	return data
+}
+
+func testSubmitSM() *SubmitSM {
+	p := &SubmitSM{
+		Header:     Header{Sequence: 2},
+		SrcAddress: SrcAddress{TON: 5, NPI: 0, Source: "sender"},
+		DstAddress: DstAddress{TON: 1, NPI: 1, Dest: "79001234567"},
+	}
+	p.ShortMessage.Message = []byte("hello")
+	p.Tags.SetUserMessageReference(7)
+	return p
+}
+
+func TestReadPDUOneByteReads(t *testing.T) {
+	for _, packet := range []interface{}{
+		&EnquireLink{Header: Header{Sequence: 1}},
+		testSubmitSM(),
+	} {
+		data := encode(t, packet)
+		p, _, header, perr := ReadPDU(iotest.OneByteReader(bytes.NewReader(data)))
+		if perr != nil {
+			t.Fatalf("%T: %v", packet, perr)
+		}
+		if int(header.CommandLength) != len(data) {
+			t.Errorf("%T: command_length %d, want %d", packet, header.CommandLength, len(data))
+		}
+		if got := encode(t, p); !bytes.Equal(got, data) {
+			t.Errorf("%T: re-encoded %x, want %x", packet, got, data)
+		}
+	}
+}
+
+func TestReadFrameCommandLength(t *testing.T) {
+	for _, length := range []uint32{0, 15, 0x10001, 0xFFFFFFFF} {
+		data := encode(t, &EnquireLink{Header: Header{Sequence: 1}})
+		binary.BigEndian.PutUint32(data, length)
+		_, _, err := ReadFrame(iotest.OneByteReader(bytes.NewReader(data)))
+		if !errors.Is(err, ErrInvalidCommandLength) {
+			t.Errorf("command_length %d: got %v", length, err)
+		}
+	}
+}
+
+func TestReadPDUBodyDisagreesWithLength(t *testing.T) {
+	// one octet too many for enquire_link
+	long := append(encode(t, &EnquireLink{Header: Header{Sequence: 1}}), 0)
+	binary.BigEndian.PutUint32(long, uint32(len(long)))
+
+	// submit_sm cut in the middle of destination_addr
+	short := encode(t, testSubmitSM())
+	short = short[:16+20]
+	binary.BigEndian.PutUint32(short, uint32(len(short)))
+
+	for name, data := range map[string][]byte{"long": long, "short": short} {
+		_, _, _, perr := ReadPDU(iotest.OneByteReader(bytes.NewReader(data)))
+		if perr == nil || perr.CommandStatus != ESME_RINVCMDLEN {
+			t.Errorf("%s: got %v, want ESME_RINVCMDLEN", name, perr)
+		}
+	}
+}
+
+func TestReadPDUTruncatedStream(t *testing.T) {
+	data := encode(t, testSubmitSM())
+	_, _, _, perr := ReadPDU(iotest.OneByteReader(bytes.NewReader(data[:len(data)-1])))
+	if perr == nil || perr.CommandStatus != ESME_ROK || !errors.Is(perr, io.ErrUnexpectedEOF) {
+		t.Errorf("got %v, want a framing error", perr)
+	}
+}
+
+func TestReadPDUStream(t *testing.T) {
+	first := encode(t, testSubmitSM())
+	second := encode(t, &EnquireLink{Header: Header{Sequence: 3}})
+	trailer := []byte{0, 0, 0}
+
+	r := bytes.NewReader(bytes.Join([][]byte{first, second, trailer}, nil))
+	or := iotest.OneByteReader(r)
+	for i, want := range [][]byte{first, second} {
+		p, _, _, perr := ReadPDU(or)
+		if perr != nil {
+			t.Fatalf("frame %d: %v", i, perr)
+		}
+		if got := encode(t, p); !bytes.Equal(got, want) {
+			t.Errorf("frame %d: %x, want %x", i, got, want)
+		}
+	}
+	if r.Len() != len(trailer) {
+		t.Errorf("%d octets left, want %d", r.Len(), len(trailer))
+	}
+}
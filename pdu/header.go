@@ -2,7 +2,6 @@ package pdu
 
 import (
 	"encoding/binary"
-	"errors"
 	"io"
 )
 
@@ -15,6 +14,42 @@ const (
 // CommandID see SMPP v5, section 4.7.5 (115p)
 type CommandID uint32
 
+const (
+	GenericNACKID           CommandID = 0x80000000
+	BindReceiverID          CommandID = 0x00000001
+	BindReceiverRespID      CommandID = 0x80000001
+	BindTransmitterID       CommandID = 0x00000002
+	BindTransmitterRespID   CommandID = 0x80000002
+	QuerySMID               CommandID = 0x00000003
+	QuerySMRespID           CommandID = 0x80000003
+	SubmitSMID              CommandID = 0x00000004
+	SubmitSMRespID          CommandID = 0x80000004
+	DeliverSMID             CommandID = 0x00000005
+	DeliverSMRespID         CommandID = 0x80000005
+	UnbindID                CommandID = 0x00000006
+	UnbindRespID            CommandID = 0x80000006
+	ReplaceSMID             CommandID = 0x00000007
+	ReplaceSMRespID         CommandID = 0x80000007
+	CancelSMID              CommandID = 0x00000008
+	CancelSMRespID          CommandID = 0x80000008
+	BindTransceiverID       CommandID = 0x00000009
+	BindTransceiverRespID   CommandID = 0x80000009
+	OutbindID               CommandID = 0x0000000B
+	EnquireLinkID           CommandID = 0x00000015
+	EnquireLinkRespID       CommandID = 0x80000015
+	SubmitMultiID           CommandID = 0x00000021
+	SubmitMultiRespID       CommandID = 0x80000021
+	AlertNotificationID     CommandID = 0x00000102
+	DataSMID                CommandID = 0x00000103
+	DataSMRespID            CommandID = 0x80000103
+	QueryBroadcastSMID      CommandID = 0x00000111
+	QueryBroadcastSMRespID  CommandID = 0x80000111
+	BroadcastSMID           CommandID = 0x00000112
+	BroadcastSMRespID       CommandID = 0x80000112
+	CancelBroadcastSMID     CommandID = 0x00000113
+	CancelBroadcastSMRespID CommandID = 0x80000113
+)
+
 // Header ...
 type Header struct {
 	CommandLength uint32        `json:"command_length"`
@@ -23,17 +58,11 @@ type Header struct {
 	Sequence      int32         `json:"sequence_number"`
 }
 
-// PDUError ...
-type PDUError struct {
-	CommandStatus CommandStatus
-	Err           error
-}
-
 // ReadPDUHeader ...
 func ReadPDUHeader(r io.Reader, header *Header) (err error) {
 	err = binary.Read(r, binary.BigEndian, header)
 	if err == nil && (header.CommandLength < 16 || header.CommandLength > 0x10000) {
-		err = errors.New("InvalidCommandLength")
+		err = ErrInvalidCommandLength
 	}
 	return
 }
@@ -4,6 +4,14 @@ import (
 	"reflect"
 )
 
+// ReadCommandID ...
+func ReadCommandID(packet interface{}) CommandID {
+	if h := getHeader(packet); h != nil {
+		return h.CommandID
+	}
+	return 0
+}
+
 // ReadSequence ...
 func ReadSequence(packet interface{}) int32 {
 	if h := getHeader(packet); h != nil {
@@ -27,6 +35,13 @@ func ReadCommandStatus(packet interface{}) CommandStatus {
 	return 0
 }
 
+// WriteCommandStatus ...
+func WriteCommandStatus(packet interface{}, status CommandStatus) {
+	if h := getHeader(packet); h != nil {
+		h.CommandStatus = status
+	}
+}
+
 // getHeader ...
 func getHeader(packet interface{}) *Header {
 	p := reflect.ValueOf(packet)
@@ -42,3 +57,40 @@ func getHeader(packet interface{}) *Header {
 	}
 	return nil
 }
+
+// ReadTags returns the optional parameters of packet, nil if it has none
+func ReadTags(packet interface{}) Tags {
+	p := reflect.ValueOf(packet)
+	if p.Kind() == reflect.Ptr {
+		p = p.Elem()
+	}
+	if p.Kind() != reflect.Struct {
+		return nil
+	}
+
+	for i := 0; i < p.NumField(); i++ {
+		if t, ok := p.Field(i).Interface().(Tags); ok {
+			return t
+		}
+	}
+	return nil
+}
+
+// ReadShortMessage returns the short message of packet, nil if it has none or is not a pointer
+func ReadShortMessage(packet interface{}) *ShortMessage {
+	p := reflect.ValueOf(packet)
+	if p.Kind() == reflect.Ptr {
+		p = p.Elem()
+	}
+	if p.Kind() != reflect.Struct || !p.CanAddr() {
+		return nil
+	}
+
+	for i := 0; i < p.NumField(); i++ {
+		field := p.Field(i)
+		if m, ok := field.Addr().Interface().(*ShortMessage); ok {
+			return m
+		}
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package pdu
+
+import "testing"
+
+func TestReadShortMessage(t *testing.T) {
+	p := &SubmitSM{}
+	p.ShortMessage.Message = []byte("hello")
+	if m := ReadShortMessage(p); m != &p.ShortMessage {
+		t.Errorf("pointer: got %p, want %p", m, &p.ShortMessage)
+	}
+	if m := ReadShortMessage(*p); m != nil {
+		t.Errorf("value: got %+v, want nil", m)
+	}
+	if m := ReadShortMessage(&EnquireLink{}); m != nil {
+		t.Errorf("enquire_link: got %+v, want nil", m)
+	}
+}
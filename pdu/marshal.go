@@ -5,15 +5,20 @@ import (
 	"bytes"
 	"encoding/binary"
 	"encoding/hex"
-	"errors"
+	"fmt"
 	"io"
 	"reflect"
 	"strconv"
 )
 
-// UnmarshalPDU ...
+// UnmarshalPDU decodes the fields of packet from r and returns the number of bytes consumed.
+// r is read ahead, so it should be bounded to a single PDU, see ReadFrame.
 func UnmarshalPDU(r io.Reader, packet interface{}) (n int64, err error) {
-	buf := bufio.NewReader(r)
+	cr := &countingReader{r: r}
+	buf := bufio.NewReader(cr)
+	defer func() {
+		n = cr.n - int64(buf.Buffered())
+	}()
 	v := reflect.ValueOf(packet)
 	if v.Kind() == reflect.Ptr {
 		v = v.Elem()
@@ -54,18 +59,39 @@ func UnmarshalPDU(r io.Reader, packet interface{}) (n int64, err error) {
 				_, err = v.ReadFrom(buf)
 			}
 		}
-		n = int64(buf.Size())
 		if err != nil {
-			err = errors.New("ErrUnmarshalPDUFailed")
+			err = fmt.Errorf("%w: %s: %w", ErrUnmarshalPDUFailed, v.Type().Field(i).Name, err)
 			return
 		}
 	}
 	return
 }
 
-// MarshalPDU ...
+// MarshalPDU writes the packet and returns its hex dump, see WritePDU to skip the dump
 func MarshalPDU(w io.Writer, packet interface{}) (string, *PDUError) {
-	var buf bytes.Buffer
+	bp := bufPool.Get().(*[]byte)
+	defer bufPool.Put(bp)
+
+	buf, perr := AppendPDU((*bp)[:0], packet)
+	*bp = buf
+	if perr != nil {
+		return "", perr
+	}
+
+	// tcp dump package
+	hashPDU := hex.EncodeToString(buf)
+
+	if _, err := w.Write(buf); err != nil {
+		return "", &PDUError{
+			Err: err,
+		}
+	}
+
+	return hashPDU, nil
+}
+
+// marshalReflect encodes packets that don't implement Appender by walking their fields
+func marshalReflect(buf *bytes.Buffer, packet interface{}) (string, *PDUError) {
 	p := reflect.ValueOf(packet)
 
 	if p.Kind() == reflect.Ptr {
@@ -76,7 +102,7 @@ func MarshalPDU(w io.Writer, packet interface{}) (string, *PDUError) {
 		field := p.Field(i)
 		switch field.Kind() {
 		case reflect.String:
-			writeCString(&buf, field.String())
+			writeCString(buf, field.String())
 
 		case reflect.Uint8:
 			buf.WriteByte(byte(field.Uint()))
@@ -109,7 +135,7 @@ func MarshalPDU(w io.Writer, packet interface{}) (string, *PDUError) {
 					}
 				}
 
-				_ = binary.Write(&buf, binary.BigEndian, v)
+				_ = binary.Write(buf, binary.BigEndian, v)
 
 				if v.CommandStatus != ESME_ROK {
 					goto write
@@ -130,7 +156,7 @@ func MarshalPDU(w io.Writer, packet interface{}) (string, *PDUError) {
 					m.Prepare(packet)
 				}
 
-				_, err := v.WriteTo(&buf)
+				_, err := v.WriteTo(buf)
 				if err != nil {
 					return "", &PDUError{
 						Err: err,
@@ -146,14 +172,5 @@ write:
 	}
 
 	// tcp dump package
-	hashPDU := hex.EncodeToString(buf.Bytes())
-
-	_, err := buf.WriteTo(w)
-	if err != nil {
-		return "", &PDUError{
-			Err: err,
-		}
-	}
-
-	return hashPDU, nil
+	return hex.EncodeToString(buf.Bytes()), nil
 }
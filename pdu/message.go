@@ -17,6 +17,9 @@ type ShortMessage struct {
 	DefaultMessageID byte // see SMPP v5, section 4.7.27 (134p)
 	UDHeader         UserDataHeader
 	Message          []byte
+
+	// Packed tells that a GSM7 Message holds packed septets, see coding.PackGSM7
+	Packed bool
 }
 
 // MarshalJSON ...
@@ -41,16 +44,29 @@ func (p *ShortMessage) ReadFrom(r io.Reader) (n int64, err error) {
 	if p.DataCoding != coding.NoCoding {
 		cod, _ := buf.ReadByte()
 		p.DataCoding = coding.DataCoding(cod)
+		n++
 	}
-	p.DefaultMessageID, err = buf.ReadByte()
-	if err == nil {
-		var length byte
-		if length, err = buf.ReadByte(); err == nil && p.UDHeader != nil {
-			_, err = p.UDHeader.ReadFrom(buf)
-		}
-		if err == nil {
-			p.Message = make([]byte, length-byte(p.UDHeader.Len()))
-			_, err = buf.Read(p.Message)
+	if p.DefaultMessageID, err = buf.ReadByte(); err != nil {
+		return
+	}
+	n++
+	var length byte
+	if length, err = buf.ReadByte(); err != nil {
+		return
+	}
+	n++
+	data := make([]byte, length)
+	read, err := io.ReadFull(buf, data)
+	n += int64(read)
+	if err != nil {
+		return
+	}
+	p.Message = data
+	if p.UDHeader != nil && len(data) > 0 {
+		var h UserDataHeader
+		var udhLength int
+		if h, udhLength, err = DecodeUserDataHeader(data); err == nil {
+			p.UDHeader, p.Message = h, data[udhLength:]
 		}
 	}
 	return
@@ -87,6 +103,34 @@ func (p *ShortMessage) Prepare(pdu interface{}) {
 	}
 }
 
+// Pack converts a GSM7 Message from one septet per octet to packed septets
+func (p *ShortMessage) Pack() {
+	if !p.Packed && isGSM7(p.DataCoding) {
+		p.Message = coding.PackGSM7(p.Message, p.UDHeader.Len())
+		p.Packed = true
+	}
+}
+
+// Unpack is the inverse of Pack
+func (p *ShortMessage) Unpack() {
+	if p.Packed && isGSM7(p.DataCoding) {
+		p.Message = coding.UnpackGSM7(p.Message, p.UDHeader.Len())
+		p.Packed = false
+	}
+}
+
+// isGSM7 reports whether the data coding uses the GSM 7 bit default alphabet
+func isGSM7(c coding.DataCoding) bool {
+	if c == coding.GSM7BitCoding {
+		return true
+	}
+	if cod, _, kind := c.MessageWaitingInfo(); kind != -1 {
+		return cod == coding.GSM7BitCoding
+	}
+	cod, _ := c.MessageClass()
+	return cod == coding.GSM7BitCoding
+}
+
 // Parse ...
 func (p *ShortMessage) Parse() (string, error) {
 	encoder := p.DataCoding.Encoding()
@@ -104,8 +148,16 @@ func (p *ShortMessage) Decode() string {
 	var message string
 
 	// is GSM7 encoding
-	if p.DataCoding == coding.GSM7BitCoding {
-		message = coding.DecodeGSM7(p.Message)
+	if isGSM7(p.DataCoding) {
+		septets := p.Message
+		if p.Packed {
+			septets = coding.UnpackGSM7(septets, p.UDHeader.Len())
+		}
+		if tables := p.UDHeader.NationalTables(); tables != (coding.NationalTables{}) {
+			message = tables.Decode(septets)
+		} else {
+			message = coding.DecodeGSM7(septets)
+		}
 
 	} else {
 		// Get encoder
@@ -132,8 +184,8 @@ func (p *ShortMessage) Decode() string {
 
 // Encode text to "dataCoding" encoding
 func EncodeMessage(message string, dataCoding coding.DataCoding) []byte {
-	switch dataCoding {
-	case coding.GSM7BitCoding:
+	switch {
+	case isGSM7(dataCoding):
 		return coding.EncodeGSM7(message)
 	default:
 		encoding := dataCoding.Encoding()
@@ -1,6 +1,7 @@
 package pdu
 
 import (
+	"encoding/json"
 	"strconv"
 	"strings"
 )
@@ -8,6 +9,19 @@ import (
 // MessageState see SMPP v5, section 4.7.15 (127p)
 type MessageState byte
 
+const (
+	StateScheduled     MessageState = 0 // SMPP v5 only
+	StateEnroute       MessageState = 1
+	StateDelivered     MessageState = 2
+	StateExpired       MessageState = 3
+	StateDeleted       MessageState = 4
+	StateUndeliverable MessageState = 5
+	StateAccepted      MessageState = 6
+	StateUnknown       MessageState = 7
+	StateRejected      MessageState = 8
+	StateSkipped       MessageState = 9
+)
+
 //goland:noinspection SpellCheckingInspection
 var messageStateMap = []string{
 	"scheduled",
@@ -22,10 +36,81 @@ var messageStateMap = []string{
 	"skipped",
 }
 
+// dlrCodes are the stat values of a delivery receipt, see SMPP v5, appendix B (288p)
+//
+//goland:noinspection SpellCheckingInspection
+var dlrCodes = []string{
+	"SCHEDULED",
+	"ENROUTE",
+	"DELIVRD",
+	"EXPIRED",
+	"DELETED",
+	"UNDELIV",
+	"ACCEPTD",
+	"UNKNOWN",
+	"REJECTD",
+	"SKIPPED",
+}
+
 // String ...
 func (m MessageState) String() string {
-	if int(m) > len(messageStateMap) {
+	if int(m) >= len(messageStateMap) {
 		return strconv.Itoa(int(m))
 	}
 	return strings.ToUpper(messageStateMap[m])
 }
+
+// DLRCode returns the stat of a delivery receipt, e.g. DELIVRD
+func (m MessageState) DLRCode() string {
+	if int(m) >= len(dlrCodes) {
+		return "UNKNOWN"
+	}
+	return dlrCodes[m]
+}
+
+// IsFinal reports whether no further state change follows
+func (m MessageState) IsFinal() bool {
+	return m != StateScheduled && m != StateEnroute
+}
+
+// ParseMessageState accepts the state name, the receipt stat or the number, in any case
+func ParseMessageState(value string) (MessageState, bool) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	for i := range messageStateMap {
+		if value == dlrCodes[i] || value == strings.ToUpper(messageStateMap[i]) {
+			return MessageState(i), true
+		}
+	}
+	if n, err := strconv.ParseUint(value, 10, 8); err == nil {
+		return MessageState(n), true
+	}
+	return 0, false
+}
+
+// MarshalJSON ...
+func (m MessageState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.String())
+}
+
+// UnmarshalJSON accepts a string, see ParseMessageState, or a number
+func (m *MessageState) UnmarshalJSON(data []byte) error {
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	switch v := value.(type) {
+	case string:
+		state, ok := ParseMessageState(v)
+		if !ok {
+			return ErrInvalidMessageState
+		}
+		*m = state
+		return nil
+	case float64:
+		if v >= 0 && v <= 0xFF && v == float64(byte(v)) {
+			*m = MessageState(v)
+			return nil
+		}
+	}
+	return ErrInvalidMessageState
+}
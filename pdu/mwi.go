@@ -0,0 +1,127 @@
+package pdu
+
+import (
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+// MWIMethod selects where a message waiting indication is carried, methods may be combined
+type MWIMethod byte
+
+const (
+	MWIDataCoding MWIMethod = 1 << iota // data_coding groups 0xC0-0xEF, see 3GPP TS 23.038, section 4
+	MWIUDH                              // special SMS indication IE 0x01, see SpecialSMSIndication
+	MWITLV                              // ms_msg_wait_facilities, see SMPP v5, section 4.8.4.40 (157p)
+)
+
+// MessageWaiting is a voicemail, fax, email or other message waiting indication.
+// Kind takes IndicationVoicemail, IndicationFax, IndicationEmail or IndicationOther.
+type MessageWaiting struct {
+	Method MWIMethod // the one the indication was read from
+	Kind   byte
+	Active bool
+	// Count of waiting messages, only the UDH carries it
+	Count byte
+	// Store keeps the message text, otherwise the handset may discard it once the indication is updated
+	Store bool
+}
+
+// DataCoding returns the data_coding of the indication, UCS2 is only available to stored messages
+func (m MessageWaiting) DataCoding(ucs2 bool) coding.DataCoding {
+	group := coding.DataCoding(0xC0) // discard, GSM7
+	switch {
+	case m.Store && ucs2:
+		group = 0xE0
+	case m.Store:
+		group = 0xD0
+	}
+	return group | coding.DataCoding(getBool(m.Active)<<3) | coding.DataCoding(m.Kind&0b11)
+}
+
+// SpecialSMSIndication returns the UDH form of the indication, an active one counts at least 1
+func (m MessageWaiting) SpecialSMSIndication() SpecialSMSIndication {
+	s := SpecialSMSIndication{Store: m.Store, Type: m.Kind & 0b11}
+	if m.Active {
+		s.Count = m.Count
+		if s.Count == 0 {
+			s.Count = 1
+		}
+	}
+	return s
+}
+
+// MsMsgWaitFacilities returns the ms_msg_wait_facilities value of the indication
+func (m MessageWaiting) MsMsgWaitFacilities() byte {
+	return getBool(m.Active)<<7 | m.Kind&0b11
+}
+
+// NewMessageWaitingSubmitSM copies template with the indication set by every method of methods
+// and text as short message. The data_coding group only carries GSM7 or, when stored, UCS2 text.
+func NewMessageWaitingSubmitSM(template *SubmitSM, mwi MessageWaiting, methods MWIMethod, text string) (*SubmitSM, error) {
+	p := cloneSubmitSM(template)
+	p.ShortMessage.UDHeader = append(UserDataHeader(nil), template.ShortMessage.UDHeader...)
+
+	dataCoding := coding.BestCoding(text, true)
+	if methods&MWIDataCoding != 0 {
+		ucs2 := dataCoding != coding.GSM7BitCoding
+		if ucs2 && !mwi.Store {
+			return nil, ErrInvalidDataCoding
+		}
+		dataCoding = mwi.DataCoding(ucs2)
+	}
+	p.ShortMessage.DataCoding = dataCoding
+	p.ShortMessage.Message = EncodeMessage(text, dataCoding)
+
+	if methods&MWIUDH != 0 {
+		p.ShortMessage.UDHeader.Remove(IESpecialSMS)
+		p.ShortMessage.UDHeader.Add(mwi.SpecialSMSIndication().IE())
+	}
+	if len(p.ShortMessage.UDHeader) > 0 {
+		p.ESMClass.UDHIndicator = true
+	}
+	udhLength := p.ShortMessage.UDHeader.Len()
+	capacity := coding.MaxOctets - udhLength
+	if isGSM7(dataCoding) {
+		// one septet per octet, 160 of them fit without UDH
+		capacity = coding.GSM7Septets(udhLength)
+	}
+	if len(p.ShortMessage.Message) > capacity {
+		return nil, ErrDataTooLarge
+	}
+
+	if methods&MWITLV != 0 {
+		p.Tags.SetMsMsgWaitFacilities(mwi.MsMsgWaitFacilities())
+	}
+	return p, nil
+}
+
+// ParseMessageWaiting returns every indication the packet, e.g. a *DeliverSM, carries in the
+// order data_coding, UDH and ms_msg_wait_facilities
+func ParseMessageWaiting(packet interface{}) (found []MessageWaiting) {
+	if m := ReadShortMessage(packet); m != nil {
+		if _, active, kind := m.DataCoding.MessageWaitingInfo(); kind != -1 {
+			found = append(found, MessageWaiting{
+				Method: MWIDataCoding,
+				Kind:   byte(kind),
+				Active: active,
+				Store:  m.DataCoding>>4 != 0xC,
+			})
+		}
+		for _, s := range m.UDHeader.SpecialSMSIndications() {
+			found = append(found, MessageWaiting{
+				Method: MWIUDH,
+				Kind:   s.Type & 0b11,
+				Active: s.Count > 0,
+				Count:  s.Count,
+				Store:  s.Store,
+			})
+		}
+	}
+	if value, ok := ReadTags(packet).MsMsgWaitFacilities(); ok {
+		found = append(found, MessageWaiting{
+			Method: MWITLV,
+			Kind:   value & 0b11,
+			Active: value&0x80 != 0,
+		})
+	}
+	return
+}
@@ -0,0 +1,41 @@
+package pdu
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewMessageWaitingSubmitSMLength(t *testing.T) {
+	mwi := MessageWaiting{Kind: IndicationVoicemail, Active: true, Store: true}
+	for _, tt := range []struct {
+		methods MWIMethod
+		text    string
+		ok      bool
+	}{
+		{MWIDataCoding, strings.Repeat("a", 150), true},
+		{MWIDataCoding, strings.Repeat("a", 160), true},
+		{MWIDataCoding, strings.Repeat("a", 161), false},
+		{MWIUDH, strings.Repeat("a", 154), true}, // UDH of 5 octets leaves 154 septets
+		{MWIUDH, strings.Repeat("a", 155), false},
+		{MWIDataCoding, strings.Repeat("中", 70), true},
+		{MWIDataCoding, strings.Repeat("中", 71), false},
+		{MWIUDH, strings.Repeat("中", 67), true},
+		{MWIUDH, strings.Repeat("中", 68), false},
+	} {
+		p, err := NewMessageWaitingSubmitSM(testSubmitSM(), mwi, tt.methods, tt.text)
+		if p != nil {
+			p.Header.Sequence = 1
+		}
+		switch {
+		case tt.ok && err != nil:
+			t.Errorf("%d characters by %d: %v", len([]rune(tt.text)), tt.methods, err)
+		case !tt.ok && !errors.Is(err, ErrDataTooLarge):
+			t.Errorf("%d characters by %d: got %v, want ErrDataTooLarge", len([]rune(tt.text)), tt.methods, err)
+		case tt.ok:
+			if _, perr := AppendPDU(nil, p); perr != nil {
+				t.Errorf("%d characters by %d: %v", len([]rune(tt.text)), tt.methods, perr)
+			}
+		}
+	}
+}
@@ -24,7 +24,7 @@ type BindTransmitter struct {
 type BindTransmitterResp struct {
 	Header   Header `id:"80000002"`
 	SystemID string `json:"system_id"`
-	Tags     `json:"Tags,omitempty"`
+	Tags     Tags   `json:"Tags,omitempty"`
 }
 
 // Resp ...
@@ -48,7 +48,7 @@ type BindReceiver struct {
 type BindReceiverResp struct {
 	Header   Header `id:"80000001"`
 	SystemID string `json:"system_id"`
-	Tags     `json:"Tags,omitempty"`
+	Tags     Tags   `json:"Tags,omitempty"`
 }
 
 // Resp ...
@@ -72,7 +72,7 @@ type BindTransceiver struct {
 type BindTransceiverResp struct {
 	Header   Header `id:"80000009"`
 	SystemID string `json:"system_id"`
-	Tags     `json:"Tags,omitempty"`
+	Tags     Tags   `json:"Tags,omitempty"`
 }
 
 // Resp ...
@@ -83,7 +83,7 @@ func (p *BindTransceiver) Resp() interface{} {
 // EnquireLink see SMPP v5, section 4.1.2.1 (63p)
 type EnquireLink struct {
 	Header Header `id:"00000015"`
-	Tags   `json:"Tags,omitempty"`
+	Tags   Tags   `json:"Tags,omitempty"`
 }
 
 // EnquireLinkResp see SMPP v5, section 4.1.2.2 (63p)
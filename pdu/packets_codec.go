@@ -0,0 +1,866 @@
+package pdu
+
+import (
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+// AppendTo ...
+func (p *BindTransmitter) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BindTransmitterID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.SystemID)
+	buf = appendCString(buf, p.Password)
+	buf = appendCString(buf, p.SystemType)
+	buf = append(buf, byte(p.Version))
+	buf = append(buf, p.TON)
+	buf = append(buf, p.NPI)
+	buf = appendCString(buf, p.AddrRange)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BindTransmitter) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SystemID = d.readCString()
+		p.Password = d.readCString()
+		p.SystemType = d.readCString()
+		p.Version = InterfaceVersion(d.readByte())
+		p.TON = d.readByte()
+		p.NPI = d.readByte()
+		p.AddrRange = d.readCString()
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *BindTransmitterResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BindTransmitterRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.SystemID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BindTransmitterResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SystemID = d.readCString()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *BindReceiver) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BindReceiverID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.SystemID)
+	buf = appendCString(buf, p.Password)
+	buf = appendCString(buf, p.SystemType)
+	buf = append(buf, byte(p.Version))
+	buf = append(buf, p.TON)
+	buf = append(buf, p.NPI)
+	buf = appendCString(buf, p.AddrRange)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BindReceiver) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SystemID = d.readCString()
+		p.Password = d.readCString()
+		p.SystemType = d.readCString()
+		p.Version = InterfaceVersion(d.readByte())
+		p.TON = d.readByte()
+		p.NPI = d.readByte()
+		p.AddrRange = d.readCString()
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *BindReceiverResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BindReceiverRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.SystemID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BindReceiverResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SystemID = d.readCString()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *BindTransceiver) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BindTransceiverID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.SystemID)
+	buf = appendCString(buf, p.Password)
+	buf = appendCString(buf, p.SystemType)
+	buf = append(buf, byte(p.Version))
+	buf = append(buf, p.TON)
+	buf = append(buf, p.NPI)
+	buf = appendCString(buf, p.AddrRange)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BindTransceiver) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SystemID = d.readCString()
+		p.Password = d.readCString()
+		p.SystemType = d.readCString()
+		p.Version = InterfaceVersion(d.readByte())
+		p.TON = d.readByte()
+		p.NPI = d.readByte()
+		p.AddrRange = d.readCString()
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *BindTransceiverResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BindTransceiverRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.SystemID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BindTransceiverResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SystemID = d.readCString()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *EnquireLink) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, EnquireLinkID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *EnquireLink) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *EnquireLinkResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, EnquireLinkRespID)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *EnquireLinkResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	d.readHeader(&p.Header)
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *SubmitSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, SubmitSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.ServiceType)
+	buf = p.SrcAddress.appendTo(buf)
+	buf = p.DstAddress.appendTo(buf)
+	c, _ := p.ESMClass.ReadByte()
+	buf = append(buf, c)
+	buf = append(buf, p.ProtocolID)
+	buf = append(buf, p.PriorityFlag)
+	buf = appendCString(buf, p.ScheduleDeliveryTime)
+	buf = appendCString(buf, p.ValidityPeriod)
+	r, _ := p.RegisteredDelivery.ReadByte()
+	buf = append(buf, r)
+	buf = appendBool(buf, p.ReplaceIfPresent)
+	if buf, err = p.ShortMessage.appendTo(buf, true, p.ESMClass.UDHIndicator); err != nil {
+		return buf, err
+	}
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *SubmitSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.ServiceType = d.readCString()
+		p.SrcAddress.decode(&d)
+		p.DstAddress.decode(&d)
+		_ = p.ESMClass.WriteByte(d.readByte())
+		p.ProtocolID = d.readByte()
+		p.PriorityFlag = d.readByte()
+		p.ScheduleDeliveryTime = d.readCString()
+		p.ValidityPeriod = d.readCString()
+		_ = p.RegisteredDelivery.WriteByte(d.readByte())
+		p.ReplaceIfPresent = d.readBool()
+		p.ShortMessage.decode(&d, true, p.ESMClass.UDHIndicator)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *SubmitSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, SubmitSMRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.MessageID)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *SubmitSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *AlertNotification) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, AlertNotificationID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = p.SourceAddr.appendTo(buf)
+	buf = p.ESMEAddr.appendTo(buf)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *AlertNotification) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SourceAddr.decode(&d)
+		p.ESMEAddr.decode(&d)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *BroadcastSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BroadcastSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.ServiceType)
+	buf = p.SourceAddr.appendTo(buf)
+	buf = appendCString(buf, p.MessageID)
+	buf = append(buf, p.PriorityFlag)
+	buf = appendCString(buf, p.ScheduleDeliveryTime)
+	buf = appendCString(buf, p.ValidityPeriod)
+	buf = appendBool(buf, p.ReplaceIfPresent)
+	buf = append(buf, byte(p.DataCoding))
+	buf = append(buf, p.DefaultMessageID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BroadcastSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.ServiceType = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.MessageID = d.readCString()
+		p.PriorityFlag = d.readByte()
+		p.ScheduleDeliveryTime = d.readCString()
+		p.ValidityPeriod = d.readCString()
+		p.ReplaceIfPresent = d.readBool()
+		p.DataCoding = coding.DataCoding(d.readByte())
+		p.DefaultMessageID = d.readByte()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *BroadcastSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, BroadcastSMRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.MessageID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *BroadcastSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *CancelBroadcastSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, CancelBroadcastSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.ServiceType)
+	buf = appendCString(buf, p.MessageID)
+	buf = p.SourceAddr.appendTo(buf)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *CancelBroadcastSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.ServiceType = d.readCString()
+		p.MessageID = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *CancelBroadcastSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, CancelBroadcastSMRespID)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *CancelBroadcastSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	d.readHeader(&p.Header)
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *CancelSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, CancelSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.ServiceType)
+	buf = appendCString(buf, p.MessageID)
+	buf = p.SourceAddr.appendTo(buf)
+	buf = p.DestAddr.appendTo(buf)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *CancelSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.ServiceType = d.readCString()
+		p.MessageID = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.DestAddr.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *CancelSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, CancelSMRespID)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *CancelSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	d.readHeader(&p.Header)
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *DataSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, DataSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.ServiceType)
+	buf = p.SourceAddr.appendTo(buf)
+	buf = p.DestAddr.appendTo(buf)
+	c, _ := p.ESMClass.ReadByte()
+	buf = append(buf, c)
+	r, _ := p.RegisteredDelivery.ReadByte()
+	buf = append(buf, r)
+	buf = append(buf, byte(p.DataCoding))
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *DataSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.ServiceType = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.DestAddr.decode(&d)
+		_ = p.ESMClass.WriteByte(d.readByte())
+		_ = p.RegisteredDelivery.WriteByte(d.readByte())
+		p.DataCoding = coding.DataCoding(d.readByte())
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *DataSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, DataSMRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.MessageID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *DataSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *DeliverSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, DeliverSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.ServiceType)
+	buf = p.SourceAddr.appendTo(buf)
+	buf = p.DestAddr.appendTo(buf)
+	c, _ := p.ESMClass.ReadByte()
+	buf = append(buf, c)
+	buf = append(buf, p.ProtocolID)
+	buf = append(buf, p.PriorityFlag)
+	buf = appendCString(buf, p.ScheduleDeliveryTime)
+	buf = appendCString(buf, p.ValidityPeriod)
+	r, _ := p.RegisteredDelivery.ReadByte()
+	buf = append(buf, r)
+	buf = appendBool(buf, p.ReplaceIfPresent)
+	if buf, err = p.Message.appendTo(buf, true, p.ESMClass.UDHIndicator); err != nil {
+		return buf, err
+	}
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *DeliverSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.ServiceType = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.DestAddr.decode(&d)
+		_ = p.ESMClass.WriteByte(d.readByte())
+		p.ProtocolID = d.readByte()
+		p.PriorityFlag = d.readByte()
+		p.ScheduleDeliveryTime = d.readCString()
+		p.ValidityPeriod = d.readCString()
+		_ = p.RegisteredDelivery.WriteByte(d.readByte())
+		p.ReplaceIfPresent = d.readBool()
+		p.Message.decode(&d, true, p.ESMClass.UDHIndicator)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *DeliverSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, DeliverSMRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.MessageID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *DeliverSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *GenericNACK) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, GenericNACKID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *GenericNACK) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *Outbind) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, OutbindID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.SystemID)
+	buf = appendCString(buf, p.Password)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *Outbind) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.SystemID = d.readCString()
+		p.Password = d.readCString()
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *QueryBroadcastSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, QueryBroadcastSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.MessageID)
+	buf = p.SourceAddr.appendTo(buf)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *QueryBroadcastSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *QueryBroadcastSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, QueryBroadcastSMRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.MessageID)
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *QueryBroadcastSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *QuerySM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, QuerySMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.MessageID)
+	buf = p.SourceAddr.appendTo(buf)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *QuerySM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.SourceAddr.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *QuerySMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, QuerySMRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	buf = appendCString(buf, p.MessageID)
+	buf = appendCString(buf, p.FinalDate)
+	buf = append(buf, byte(p.MessageState))
+	buf = append(buf, byte(p.ErrorCode)) // error_code is a single octet
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *QuerySMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.FinalDate = d.readCString()
+		p.MessageState = MessageState(d.readByte())
+		p.ErrorCode = CommandStatus(d.readByte())
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *ReplaceSM) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, ReplaceSMID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.MessageID)
+	buf = p.SourceAddr.appendTo(buf)
+	buf = appendCString(buf, p.ScheduleDeliveryTime)
+	buf = appendCString(buf, p.ValidityPeriod)
+	r, _ := p.RegisteredDelivery.ReadByte()
+	buf = append(buf, r)
+	if buf, err = p.Message.appendTo(buf, false, false); err != nil {
+		return buf, err
+	}
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *ReplaceSM) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.ScheduleDeliveryTime = d.readCString()
+		p.ValidityPeriod = d.readCString()
+		_ = p.RegisteredDelivery.WriteByte(d.readByte())
+		p.Message.decode(&d, false, false)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *ReplaceSMResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, ReplaceSMRespID)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *ReplaceSMResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	d.readHeader(&p.Header)
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *SubmitMulti) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, SubmitMultiID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.ServiceType)
+	buf = p.SourceAddr.appendTo(buf)
+	if buf, err = p.DestAddrList.appendTo(buf); err != nil {
+		return buf, err
+	}
+	c, _ := p.ESMClass.ReadByte()
+	buf = append(buf, c)
+	buf = append(buf, p.ProtocolID)
+	buf = append(buf, p.PriorityFlag)
+	buf = appendCString(buf, p.ScheduleDeliveryTime)
+	buf = appendCString(buf, p.ValidityPeriod)
+	r, _ := p.RegisteredDelivery.ReadByte()
+	buf = append(buf, r)
+	buf = appendBool(buf, p.ReplaceIfPresent)
+	if buf, err = p.Message.appendTo(buf, true, p.ESMClass.UDHIndicator); err != nil {
+		return buf, err
+	}
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *SubmitMulti) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.ServiceType = d.readCString()
+		p.SourceAddr.decode(&d)
+		p.DestAddrList.decode(&d)
+		_ = p.ESMClass.WriteByte(d.readByte())
+		p.ProtocolID = d.readByte()
+		p.PriorityFlag = d.readByte()
+		p.ScheduleDeliveryTime = d.readCString()
+		p.ValidityPeriod = d.readCString()
+		_ = p.RegisteredDelivery.WriteByte(d.readByte())
+		p.ReplaceIfPresent = d.readBool()
+		p.Message.decode(&d, true, p.ESMClass.UDHIndicator)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *SubmitMultiResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, SubmitMultiRespID)
+	if p.Header.CommandStatus != ESME_ROK {
+		return finishPDU(buf, start), nil
+	}
+	var err error
+	buf = appendCString(buf, p.MessageID)
+	if buf, err = p.UnsuccessfulSMEs.appendTo(buf); err != nil {
+		return buf, err
+	}
+	if buf, err = p.Tags.appendTo(buf); err != nil {
+		return buf, err
+	}
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *SubmitMultiResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.readHeader(&p.Header) {
+		p.MessageID = d.readCString()
+		p.UnsuccessfulSMEs.decode(&d)
+		p.Tags.decode(&d)
+	}
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *Unbind) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, UnbindID)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *Unbind) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	d.readHeader(&p.Header)
+	return d.finish(&p.Header)
+}
+
+// AppendTo ...
+func (p *UnbindResp) AppendTo(buf []byte) ([]byte, error) {
+	buf, start := appendHeader(buf, &p.Header, UnbindRespID)
+	return finishPDU(buf, start), nil
+}
+
+// UnmarshalBinary ...
+func (p *UnbindResp) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	d.readHeader(&p.Header)
+	return d.finish(&p.Header)
+}
@@ -1,55 +1,30 @@
 package pdu
 
 import (
-	"bytes"
 	"encoding/hex"
 	"io"
-	"reflect"
 )
 
 // PDURequest ...
 type PDURequest struct{}
 
-// ReadPDU ...
+// ReadPDU reads one frame and decodes it. A zero CommandStatus in the returned error
+// means the frame itself could not be read and the stream is unusable.
 func ReadPDU(r io.Reader) (interface{}, string, *Header, *PDUError) {
-	var pdu interface{}
-	var buf bytes.Buffer
-
-	r = io.TeeReader(r, &buf)
-	header := new(Header)
-
-	if err := ReadPDUHeader(r, header); err != nil {
-		// tcp dump package (Header only)
-		return nil, hex.EncodeToString(buf.Bytes()), header, &PDUError{
-			Err: err,
-		}
-	}
-
-	_, err := r.Read(make([]byte, header.CommandLength-16))
+	frame, header, err := ReadFrame(r)
 
 	// tcp dump package
-	hashPDU := hex.EncodeToString(buf.Bytes())
+	hashPDU := hex.EncodeToString(frame)
 
 	if err != nil {
 		return nil, hashPDU, header, &PDUError{
-			Err:           err,
-			CommandStatus: ESME_RINVCMDLEN, // 2 - Invalid Command Length
+			Err: err,
 		}
 	}
 
-	if t, ok := Types[header.CommandID]; !ok {
-		return nil, hashPDU, header, &PDUError{
-			CommandStatus: ESME_RINVCMDID, // 3 - Invalid Command ID
-		}
-
-	} else {
-		pdu = reflect.New(t).Interface()
-		if _, err := UnmarshalPDU(&buf, pdu); err != nil {
-			return nil, hashPDU, header, &PDUError{
-				Err:           err,
-				CommandStatus: ESME_RINVCMDLEN, // 2 - Invalid Command Length
-			}
-		}
+	pdu, header, perr := DecodePDU(frame)
+	if perr != nil {
+		return nil, hashPDU, header, perr
 	}
 
 	return pdu, hashPDU, header, nil
@@ -0,0 +1,273 @@
+package pdu
+
+import (
+	"sync"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+// Reassembled is a multipart message put back together
+type Reassembled struct {
+	SourceAddr SrcAddress
+	DestAddr   DstAddress
+	Reference  uint16
+	TotalParts byte
+	// Parts are ordered by sequence, a missing part is nil
+	Parts []*DeliverSM
+	// Text is the decoded message, missing parts are skipped
+	Text string
+}
+
+// Complete reports whether every part has arrived
+func (m *Reassembled) Complete() bool {
+	for _, p := range m.Parts {
+		if p == nil {
+			return false
+		}
+	}
+	return true
+}
+
+type groupKey struct {
+	source, dest string
+	reference    uint16
+	total        byte
+}
+
+type group struct {
+	msg      *Reassembled
+	received int
+	timer    *time.Timer
+	prev     *group
+	next     *group
+	key      groupKey
+}
+
+type tombstone struct {
+	key     groupKey
+	expires time.Time
+}
+
+// Tombstone defaults when TTL or MaxPending is zero
+const (
+	tombstoneTTL = time.Minute
+	tombstoneMax = 1024
+)
+
+// Reassembler collects the parts of concatenated deliver_sm, announced by the UDH or
+// by SAR TLVs. Parts may arrive out of order, retransmitted parts are dropped, also
+// after completion: the message is remembered for TTL, up to MaxPending completed messages.
+type Reassembler struct {
+	// TTL expires an incomplete message, zero keeps it until MaxPending evicts it
+	TTL time.Duration
+	// MaxPending bounds the incomplete messages, the oldest is evicted first, zero is unbounded
+	MaxPending int
+	// OnExpire receives incomplete messages dropped by TTL or MaxPending, possibly from a timer goroutine
+	OnExpire func(m *Reassembled)
+
+	mu     sync.Mutex
+	groups map[groupKey]*group
+	// oldest and newest of the list of groups in arrival order
+	oldest *group
+	newest *group
+	// completed messages in completion order, late parts of them are dropped
+	completed  map[groupKey]time.Time
+	tombstones []tombstone
+}
+
+// NewReassembler ...
+func NewReassembler(ttl time.Duration, maxPending int) *Reassembler {
+	return &Reassembler{TTL: ttl, MaxPending: maxPending}
+}
+
+// Add stores a part and returns the message once all parts have arrived.
+// A deliver_sm that isn't concatenated is returned as a message of one part.
+func (r *Reassembler) Add(p *DeliverSM) (*Reassembled, bool) {
+	ref, total, seq, ok := concatInfo(p)
+	if !ok {
+		m := &Reassembled{SourceAddr: p.SourceAddr, DestAddr: p.DestAddr, TotalParts: 1, Parts: []*DeliverSM{p}}
+		m.Text = joinParts(m.Parts)
+		return m, true
+	}
+
+	key := groupKey{p.SourceAddr.Source, p.DestAddr.Dest, ref, total}
+	var evicted []*Reassembled
+
+	r.mu.Lock()
+	if r.groups == nil {
+		r.groups = make(map[groupKey]*group)
+		r.completed = make(map[groupKey]time.Time)
+	}
+	r.pruneTombstones(time.Now())
+	if _, ok := r.completed[key]; ok {
+		r.mu.Unlock()
+		return nil, false
+	}
+	g, found := r.groups[key]
+	if !found {
+		g = &group{key: key, msg: &Reassembled{
+			SourceAddr: p.SourceAddr, DestAddr: p.DestAddr,
+			Reference: ref, TotalParts: total, Parts: make([]*DeliverSM, total),
+		}}
+		r.groups[key] = g
+		r.push(g)
+		if r.TTL > 0 {
+			g.timer = time.AfterFunc(r.TTL, func() { r.expire(g) })
+		}
+		for r.MaxPending > 0 && len(r.groups) > r.MaxPending {
+			evicted = append(evicted, r.remove(r.oldest).msg)
+		}
+	}
+
+	var done *Reassembled
+	if r.groups[key] == g && g.msg.Parts[seq-1] == nil {
+		g.msg.Parts[seq-1] = p
+		g.received++
+		if g.received == int(total) {
+			done = r.remove(g).msg
+			r.bury(key)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, m := range evicted {
+		r.expired(m)
+	}
+	if done == nil {
+		return nil, false
+	}
+	done.Text = joinParts(done.Parts)
+	return done, true
+}
+
+// Len returns the number of incomplete messages
+func (r *Reassembler) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.groups)
+}
+
+// bury remembers a completed message, the caller holds mu
+func (r *Reassembler) bury(key groupKey) {
+	ttl, max := r.TTL, r.MaxPending
+	if ttl <= 0 {
+		ttl = tombstoneTTL
+	}
+	if max <= 0 {
+		max = tombstoneMax
+	}
+	expires := time.Now().Add(ttl)
+	r.completed[key] = expires
+	r.tombstones = append(r.tombstones, tombstone{key, expires})
+	for len(r.tombstones) > max {
+		r.unbury()
+	}
+}
+
+// pruneTombstones forgets the completed messages whose tombstone expired by now, the caller holds mu
+func (r *Reassembler) pruneTombstones(now time.Time) {
+	for len(r.tombstones) > 0 && !now.Before(r.tombstones[0].expires) {
+		r.unbury()
+	}
+}
+
+// unbury forgets the oldest completed message
+func (r *Reassembler) unbury() {
+	t := r.tombstones[0]
+	r.tombstones[0] = tombstone{}
+	r.tombstones = r.tombstones[1:]
+	if r.completed[t.key] == t.expires {
+		delete(r.completed, t.key)
+	}
+}
+
+func (r *Reassembler) expire(g *group) {
+	r.mu.Lock()
+	if r.groups[g.key] != g {
+		r.mu.Unlock()
+		return
+	}
+	r.remove(g)
+	r.mu.Unlock()
+	r.expired(g.msg)
+}
+
+func (r *Reassembler) expired(m *Reassembled) {
+	if r.OnExpire != nil {
+		m.Text = joinParts(m.Parts)
+		r.OnExpire(m)
+	}
+}
+
+func (r *Reassembler) push(g *group) {
+	g.prev = r.newest
+	if r.newest != nil {
+		r.newest.next = g
+	} else {
+		r.oldest = g
+	}
+	r.newest = g
+}
+
+// remove unlinks g, the caller holds mu
+func (r *Reassembler) remove(g *group) *group {
+	delete(r.groups, g.key)
+	if g.timer != nil {
+		g.timer.Stop()
+	}
+	if g.prev != nil {
+		g.prev.next = g.next
+	} else {
+		r.oldest = g.next
+	}
+	if g.next != nil {
+		g.next.prev = g.prev
+	} else {
+		r.newest = g.prev
+	}
+	g.prev, g.next = nil, nil
+	return g
+}
+
+// concatInfo returns the concatenation of p from its UDH or its SAR TLVs
+func concatInfo(p *DeliverSM) (ref uint16, total, seq byte, ok bool) {
+	if h := p.Message.UDHeader.ConcatenatedHeader(); h != nil {
+		ref, total, seq = h.Reference, h.TotalParts, h.Sequence
+	} else {
+		var hasRef, hasTotal, hasSeq bool
+		ref, hasRef = p.Tags.SarMsgRefNum()
+		total, hasTotal = p.Tags.SarTotalSegments()
+		seq, hasSeq = p.Tags.SarSegmentSeqnum()
+		if !hasRef || !hasTotal || !hasSeq {
+			return 0, 0, 0, false
+		}
+	}
+	return ref, total, seq, total > 1 && seq >= 1 && seq <= total
+}
+
+// joinParts decodes the parts as one message, so characters cut between parts survive
+func joinParts(parts []*DeliverSM) string {
+	var joined ShortMessage
+	var first bool
+	for _, p := range parts {
+		if p == nil {
+			continue
+		}
+		m := p.Message
+		if !first {
+			first = true
+			joined.DataCoding = m.DataCoding
+			joined.UDHeader = UserDataHeader{}
+			joined.UDHeader.SetNationalTables(m.UDHeader.NationalTables())
+		}
+		data := m.Message
+		if len(data) == 0 {
+			data, _ = p.Tags.MessagePayload()
+		} else if m.Packed && isGSM7(m.DataCoding) {
+			data = coding.UnpackGSM7(data, m.UDHeader.Len())
+		}
+		joined.Message = append(joined.Message, data...)
+	}
+	return joined.Decode()
+}
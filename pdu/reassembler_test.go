@@ -0,0 +1,138 @@
+package pdu
+
+import (
+	"testing"
+	"time"
+)
+
+// udhPart is part seq of total of the message ref, tied by the 8-bit concatenation IE
+func udhPart(ref, total, seq byte, text string) *DeliverSM {
+	p := &DeliverSM{SourceAddr: SrcAddress{Source: "100"}, DestAddr: DstAddress{Dest: "200"}}
+	p.Message.UDHeader = UserDataHeader{{IEConcatenated8, []byte{ref, total, seq}}}
+	p.Message.Message = []byte(text)
+	return p
+}
+
+// sarPart is like udhPart with SAR TLVs
+func sarPart(ref uint16, total, seq byte, text string) *DeliverSM {
+	p := &DeliverSM{SourceAddr: SrcAddress{Source: "100"}, DestAddr: DstAddress{Dest: "200"}}
+	p.Tags.SetSarMsgRefNum(ref)
+	p.Tags.SetSarTotalSegments(total)
+	p.Tags.SetSarSegmentSeqnum(seq)
+	p.Message.Message = []byte(text)
+	return p
+}
+
+// feed adds parts and returns the message completed by the last one
+func feed(t *testing.T, r *Reassembler, parts ...*DeliverSM) (*Reassembled, bool) {
+	t.Helper()
+	for i, p := range parts[:len(parts)-1] {
+		if m, ok := r.Add(p); ok {
+			t.Fatalf("complete after part %d: %q", i+1, m.Text)
+		}
+	}
+	return r.Add(parts[len(parts)-1])
+}
+
+func TestReassemblerOutOfOrder(t *testing.T) {
+	for name, parts := range map[string][]*DeliverSM{
+		"udh": {udhPart(1, 3, 3, "three"), udhPart(1, 3, 1, "one "), udhPart(1, 3, 2, "two ")},
+		"sar": {sarPart(0x1234, 3, 2, "two "), sarPart(0x1234, 3, 3, "three"), sarPart(0x1234, 3, 1, "one ")},
+	} {
+		r := NewReassembler(0, 0)
+		m, ok := feed(t, r, parts...)
+		if !ok || !m.Complete() || m.Text != "one two three" || m.TotalParts != 3 {
+			t.Errorf("%s: got %+v %t", name, m, ok)
+		}
+		if n := r.Len(); n != 0 {
+			t.Errorf("%s: %d pending", name, n)
+		}
+	}
+}
+
+func TestReassemblerSARReference(t *testing.T) {
+	r := NewReassembler(0, 0)
+	// the 16-bit references differ in the high octet only
+	r.Add(sarPart(0x0101, 2, 1, "a"))
+	r.Add(sarPart(0x0201, 2, 1, "b"))
+	if n := r.Len(); n != 2 {
+		t.Fatalf("%d pending, want 2", n)
+	}
+	if m, ok := r.Add(sarPart(0x0201, 2, 2, "c")); !ok || m.Reference != 0x0201 || m.Text != "bc" {
+		t.Errorf("got %+v %t", m, ok)
+	}
+}
+
+func TestReassemblerDuplicates(t *testing.T) {
+	r := NewReassembler(0, 0)
+	first := udhPart(1, 2, 1, "a")
+	m, ok := feed(t, r, first, udhPart(1, 2, 1, "x"), udhPart(1, 2, 2, "b"))
+	if !ok || m.Parts[0] != first || m.Text != "ab" {
+		t.Fatalf("got %+v %t, want the first copy kept", m, ok)
+	}
+
+	// late retransmissions of the completed message open no new one
+	for _, p := range []*DeliverSM{udhPart(1, 2, 1, "a"), udhPart(1, 2, 2, "b")} {
+		if m, ok := r.Add(p); ok {
+			t.Errorf("late part completed %q", m.Text)
+		}
+	}
+	if n := r.Len(); n != 0 {
+		t.Errorf("%d pending after late parts", n)
+	}
+}
+
+func TestReassemblerTombstoneBounds(t *testing.T) {
+	r := NewReassembler(20*time.Millisecond, 1)
+	feed(t, r, udhPart(1, 2, 1, "a"), udhPart(1, 2, 2, "b"))
+	feed(t, r, udhPart(2, 2, 1, "a"), udhPart(2, 2, 2, "b"))
+	// MaxPending forgot the first message, the second is remembered for TTL
+	r.Add(udhPart(1, 2, 1, "a"))
+	r.Add(udhPart(2, 2, 1, "a"))
+	if n := r.Len(); n != 1 {
+		t.Fatalf("%d pending, want the first message again", n)
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	if m, ok := feed(t, r, udhPart(2, 2, 1, "c"), udhPart(2, 2, 2, "d")); !ok || m.Text != "cd" {
+		t.Errorf("after the TTL: got %+v %t", m, ok)
+	}
+}
+
+func TestReassemblerTTL(t *testing.T) {
+	expired := make(chan *Reassembled, 1)
+	r := NewReassembler(10*time.Millisecond, 0)
+	r.OnExpire = func(m *Reassembled) { expired <- m }
+
+	r.Add(udhPart(1, 2, 2, "b"))
+	select {
+	case m := <-expired:
+		if m.Complete() || m.Parts[1] == nil || m.Text != "b" {
+			t.Errorf("expired %+v", m)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnExpire not called")
+	}
+	if n := r.Len(); n != 0 {
+		t.Errorf("%d pending", n)
+	}
+}
+
+func TestReassemblerMaxPending(t *testing.T) {
+	var evicted []uint16
+	r := NewReassembler(0, 2)
+	r.OnExpire = func(m *Reassembled) { evicted = append(evicted, m.Reference) }
+
+	r.Add(udhPart(1, 2, 1, "a"))
+	r.Add(udhPart(2, 3, 1, "a"))
+	r.Add(udhPart(3, 2, 1, "a"))
+	// a part of a pending message doesn't make it newer
+	r.Add(udhPart(2, 3, 2, "b"))
+	r.Add(udhPart(4, 2, 1, "a"))
+	if len(evicted) != 2 || evicted[0] != 1 || evicted[1] != 2 {
+		t.Errorf("evicted %v, want [1 2]", evicted)
+	}
+	if n := r.Len(); n != 2 {
+		t.Errorf("%d pending, want 2", n)
+	}
+}
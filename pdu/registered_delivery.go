@@ -13,6 +13,39 @@ type RegisteredDelivery struct {
 	Reserved                    byte // *** _ __ __
 }
 
+// Receipt request levels of RegisteredDelivery.MCDeliveryReceipt
+const (
+	ReceiptNone      byte = 0b00
+	ReceiptAlways    byte = 0b01 // on success or failure
+	ReceiptOnFailure byte = 0b10
+	ReceiptOnSuccess byte = 0b11 // SMPP v5 only
+)
+
+// Acknowledgement requests of RegisteredDelivery.SMEOriginatedAcknowledgment
+const (
+	AckNone     byte = 0b00
+	AckDelivery byte = 0b01
+	AckManual   byte = 0b10
+	AckBoth     byte = 0b11
+)
+
+// WantsReceiptFor reports whether a receipt or, for a state that isn't final,
+// an intermediate notification is requested
+func (r RegisteredDelivery) WantsReceiptFor(state MessageState) bool {
+	if !state.IsFinal() {
+		return r.IntermediateNotification
+	}
+	switch r.MCDeliveryReceipt {
+	case ReceiptAlways:
+		return true
+	case ReceiptOnFailure:
+		return state != StateDelivered
+	case ReceiptOnSuccess:
+		return state == StateDelivered
+	}
+	return false
+}
+
 // ReadByte ...
 func (r RegisteredDelivery) ReadByte() (c byte, err error) {
 	c |= r.MCDeliveryReceipt & 0b11
@@ -42,3 +75,12 @@ func (r RegisteredDelivery) MarshalJSON() (data []byte, err error) {
 	c, _ := r.ReadByte()
 	return json.Marshal(c)
 }
+
+// UnmarshalJSON ...
+func (r *RegisteredDelivery) UnmarshalJSON(data []byte) error {
+	var c byte
+	if err := json.Unmarshal(data, &c); err != nil {
+		return err
+	}
+	return r.WriteByte(c)
+}
@@ -0,0 +1,336 @@
+package pdu
+
+import (
+	"bytes"
+	"io"
+	"mime/quotedprintable"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Nokia Smart Messaging ports, see Smart Messaging Specification 3.0.0, section 3.2
+const (
+	VCardPort     uint16 = 9204
+	VCalendarPort uint16 = 9205
+)
+
+// VCardPhone is a TEL property, Type holds its parameters, e.g. "CELL" or "HOME;PREF"
+type VCardPhone struct {
+	Type   string
+	Number string
+}
+
+// VCard is a vCard 2.1 business card, see the versit vCard 2.1 specification
+type VCard struct {
+	Name          string // N, e.g. "Doe;John"
+	FormattedName string // FN
+	Phones        []VCardPhone
+	Email         string
+	Org           string
+	Title         string
+	URL           string
+	Note          string
+}
+
+// MarshalText ...
+func (c VCard) MarshalText() ([]byte, error) {
+	var b bytes.Buffer
+	b.WriteString("BEGIN:VCARD\r\nVERSION:2.1\r\n")
+	writeVProperty(&b, "N", "", c.Name)
+	writeVProperty(&b, "FN", "", c.FormattedName)
+	for _, phone := range c.Phones {
+		writeVProperty(&b, "TEL", phone.Type, phone.Number)
+	}
+	writeVProperty(&b, "EMAIL", "INTERNET", c.Email)
+	writeVProperty(&b, "ORG", "", c.Org)
+	writeVProperty(&b, "TITLE", "", c.Title)
+	writeVProperty(&b, "URL", "", c.URL)
+	writeVProperty(&b, "NOTE", "", c.Note)
+	b.WriteString("END:VCARD\r\n")
+	return b.Bytes(), nil
+}
+
+// UnmarshalText ignores the properties VCard has no field for
+func (c *VCard) UnmarshalText(data []byte) error {
+	props, err := parseVObject(data, "VCARD")
+	if err != nil {
+		return err
+	}
+	*c = VCard{}
+	for _, p := range props {
+		switch p.name {
+		case "N":
+			c.Name = p.value
+		case "FN":
+			c.FormattedName = p.value
+		case "TEL":
+			c.Phones = append(c.Phones, VCardPhone{Type: p.params, Number: p.value})
+		case "EMAIL":
+			c.Email = p.value
+		case "ORG":
+			c.Org = p.value
+		case "TITLE":
+			c.Title = p.value
+		case "URL":
+			c.URL = p.value
+		case "NOTE":
+			c.Note = p.value
+		}
+	}
+	return nil
+}
+
+// VCalendar is a vCalendar 1.0 entry, an event or a to-do when Todo is set
+type VCalendar struct {
+	Todo        bool
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time // DTSTART
+	End         time.Time // DTEND of an event, DUE of a to-do
+}
+
+const vCalendarTime = "20060102T150405Z"
+
+// MarshalText ...
+func (c VCalendar) MarshalText() ([]byte, error) {
+	component := "VEVENT"
+	end := "DTEND"
+	if c.Todo {
+		component, end = "VTODO", "DUE"
+	}
+	var b bytes.Buffer
+	b.WriteString("BEGIN:VCALENDAR\r\nVERSION:1.0\r\nBEGIN:" + component + "\r\n")
+	writeVProperty(&b, "SUMMARY", "", c.Summary)
+	writeVProperty(&b, "DESCRIPTION", "", c.Description)
+	writeVProperty(&b, "LOCATION", "", c.Location)
+	if !c.Start.IsZero() {
+		writeVProperty(&b, "DTSTART", "", c.Start.UTC().Format(vCalendarTime))
+	}
+	if !c.End.IsZero() {
+		writeVProperty(&b, end, "", c.End.UTC().Format(vCalendarTime))
+	}
+	b.WriteString("END:" + component + "\r\nEND:VCALENDAR\r\n")
+	return b.Bytes(), nil
+}
+
+// UnmarshalText reads the first VEVENT or VTODO, times without a zone are taken as UTC
+func (c *VCalendar) UnmarshalText(data []byte) error {
+	props, err := parseVObject(data, "VCALENDAR")
+	if err != nil {
+		return err
+	}
+	*c = VCalendar{}
+	var inside bool
+	for _, p := range props {
+		switch {
+		case p.name == "BEGIN" && !inside:
+			inside = p.value == "VEVENT" || p.value == "VTODO"
+			c.Todo = p.value == "VTODO"
+		case p.name == "END" && inside:
+			return nil
+		case !inside:
+		case p.name == "SUMMARY":
+			c.Summary = p.value
+		case p.name == "DESCRIPTION":
+			c.Description = p.value
+		case p.name == "LOCATION":
+			c.Location = p.value
+		case p.name == "DTSTART":
+			c.Start, err = parseVCalendarTime(p.value)
+		case p.name == "DTEND" || p.name == "DUE":
+			c.End, err = parseVCalendarTime(p.value)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !inside {
+		return ErrInvalidVCard
+	}
+	return nil
+}
+
+func parseVCalendarTime(value string) (time.Time, error) {
+	for _, layout := range []string{vCalendarTime, "20060102T150405", "20060102"} {
+		if t, err := time.ParseInLocation(layout, value, time.UTC); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, ErrInvalidVCard
+}
+
+// NewVCardSubmitSM builds the submit_sm parts carrying the card to VCardPort
+func NewVCardSubmitSM(template *SubmitSM, card VCard, opts SplitOptions) ([]*SubmitSM, error) {
+	data, _ := card.MarshalText()
+	return newPortSubmitSM(template, VCardPort, 0, data, opts)
+}
+
+// NewVCalendarSubmitSM builds the submit_sm parts carrying the entry to VCalendarPort
+func NewVCalendarSubmitSM(template *SubmitSM, entry VCalendar, opts SplitOptions) ([]*SubmitSM, error) {
+	data, _ := entry.MarshalText()
+	return newPortSubmitSM(template, VCalendarPort, 0, data, opts)
+}
+
+// ParseVCardDeliverSM joins the parts of a card sent to VCardPort, in any order
+func ParseVCardDeliverSM(parts ...*DeliverSM) (*VCard, error) {
+	data, err := joinBinary(VCardPort, deliverSMMessages(parts))
+	if err != nil {
+		return nil, err
+	}
+	card := &VCard{}
+	if err = card.UnmarshalText(data); err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// ParseVCalendarDeliverSM joins the parts of an entry sent to VCalendarPort, in any order
+func ParseVCalendarDeliverSM(parts ...*DeliverSM) (*VCalendar, error) {
+	data, err := joinBinary(VCalendarPort, deliverSMMessages(parts))
+	if err != nil {
+		return nil, err
+	}
+	entry := &VCalendar{}
+	if err = entry.UnmarshalText(data); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// newPortSubmitSM splits data into 8-bit parts with a 16-bit port UDH
+func newPortSubmitSM(template *SubmitSM, dest, src uint16, data []byte, opts SplitOptions) ([]*SubmitSM, error) {
+	t := *template
+	t.ShortMessage.UDHeader = append(UserDataHeader{}, template.ShortMessage.UDHeader...)
+	t.ShortMessage.UDHeader.Remove(IEPorts8)
+	t.ShortMessage.UDHeader.Set(PortAddress{Dest: dest, Src: src, Wide: true}.IE())
+	return SplitBinarySubmitSM(&t, data, opts)
+}
+
+// deliverSMMessages returns the short messages of parts, a message_payload is decoded in place
+// of an empty short_message
+func deliverSMMessages(parts []*DeliverSM) []*ShortMessage {
+	messages := make([]*ShortMessage, 0, len(parts))
+	for _, p := range parts {
+		m := &p.Message
+		if payload, ok := p.Tags.MessagePayload(); ok && len(m.Message) == 0 {
+			m = &ShortMessage{DataCoding: m.DataCoding, Message: payload}
+			if p.ESMClass.UDHIndicator {
+				if h, n, err := DecodeUserDataHeader(payload); err == nil {
+					m.UDHeader, m.Message = h, payload[n:]
+				}
+			}
+		}
+		messages = append(messages, m)
+	}
+	return messages
+}
+
+// writeVProperty writes a non-empty property, values with line breaks or non-ASCII text
+// are quoted-printable as vCard 2.1 and vCalendar 1.0 expect
+func writeVProperty(b *bytes.Buffer, name, params, value string) {
+	if value == "" {
+		return
+	}
+	b.WriteString(name)
+	if params != "" {
+		b.WriteString(";" + params)
+	}
+	if !needsQuotedPrintable(value) {
+		b.WriteString(":" + value + "\r\n")
+		return
+	}
+	b.WriteString(";ENCODING=QUOTED-PRINTABLE")
+	if utf8.RuneCountInString(value) != len(value) {
+		b.WriteString(";CHARSET=UTF-8")
+	}
+	b.WriteString(":")
+	w := quotedprintable.NewWriter(b)
+	w.Binary = true // line breaks of the value are escaped
+	_, _ = w.Write([]byte(value))
+	_ = w.Close()
+	b.WriteString("\r\n")
+}
+
+func needsQuotedPrintable(value string) bool {
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c >= 0x80 || c == '\r' || c == '\n' {
+			return true
+		}
+	}
+	return false
+}
+
+type vProperty struct {
+	name   string
+	params string // without ENCODING and CHARSET
+	value  string
+}
+
+// parseVObject unfolds and decodes the properties between BEGIN and END of the object
+func parseVObject(data []byte, object string) ([]vProperty, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	var props []vProperty
+	var begun bool
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		// folded lines start with white space
+		for i+1 < len(lines) && (strings.HasPrefix(lines[i+1], " ") || strings.HasPrefix(lines[i+1], "\t")) {
+			i++
+			line += lines[i][1:]
+		}
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		colon := strings.IndexByte(line, ':')
+		if colon < 0 {
+			return nil, ErrInvalidVCard
+		}
+		p := vProperty{value: line[colon+1:]}
+		fields := strings.Split(line[:colon], ";")
+		p.name = strings.ToUpper(fields[0])
+		var params []string
+		var qp bool
+		for _, param := range fields[1:] {
+			switch upper := strings.ToUpper(param); {
+			case upper == "ENCODING=QUOTED-PRINTABLE" || upper == "QUOTED-PRINTABLE":
+				qp = true
+			case strings.HasPrefix(upper, "ENCODING=") || strings.HasPrefix(upper, "CHARSET="):
+			default:
+				params = append(params, param)
+			}
+		}
+		p.params = strings.Join(params, ";")
+		if qp {
+			// soft line breaks end a line with '='
+			for strings.HasSuffix(p.value, "=") && i+1 < len(lines) {
+				i++
+				p.value += "\r\n" + lines[i]
+			}
+			value, err := io.ReadAll(quotedprintable.NewReader(strings.NewReader(p.value)))
+			if err != nil {
+				return nil, ErrInvalidVCard
+			}
+			p.value = string(value)
+		}
+
+		switch {
+		case p.name == "BEGIN" && strings.EqualFold(p.value, object):
+			begun = true
+			continue
+		case p.name == "END" && strings.EqualFold(p.value, object):
+			if !begun {
+				return nil, ErrInvalidVCard
+			}
+			return props, nil
+		case !begun:
+			return nil, ErrInvalidVCard
+		}
+		if p.name == "BEGIN" || p.name == "END" {
+			p.value = strings.ToUpper(p.value)
+		}
+		props = append(props, p)
+	}
+	return nil, ErrInvalidVCard
+}
@@ -0,0 +1,153 @@
+package pdu
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SMPPTime is an absolute or relative time "YYMMDDhhmmsstnnp", see SMPP v5, section 4.7.23.4 (132p).
+// The zero value is the empty time, i.e. immediate delivery or the default validity.
+type SMPPTime struct {
+	// Absolute is set for absolute times, in the UTC offset of the encoded value
+	Absolute time.Time
+
+	// Relative times count from the MC's current time
+	Relative bool
+	Years    int
+	Months   int
+	Days     int
+	Hours    int
+	Minutes  int
+	Seconds  int
+}
+
+// NewAbsoluteTime ...
+func NewAbsoluteTime(t time.Time) SMPPTime {
+	return SMPPTime{Absolute: t}
+}
+
+// NewRelativeTime splits d into days, hours, minutes and seconds, days beyond 99 carry into
+// 30 day months and months beyond 99 into years. Durations beyond 99 years give 99 years.
+func NewRelativeTime(d time.Duration) SMPPTime {
+	if d < 0 {
+		d = 0
+	}
+	secs := int64(d / time.Second)
+	t := SMPPTime{Relative: true}
+	t.Seconds, secs = int(secs%60), secs/60
+	t.Minutes, secs = int(secs%60), secs/60
+	t.Hours, secs = int(secs%24), secs/24
+	t.Days = int(secs)
+	if t.Days > 99 {
+		t.Months, t.Days = t.Days/30, t.Days%30
+	}
+	if t.Months > 99 {
+		t.Years, t.Months = t.Months/12, t.Months%12
+	}
+	if t.Years > 99 {
+		return SMPPTime{Relative: true, Years: 99}
+	}
+	return t
+}
+
+// ParseSMPPTime parses an absolute or relative time, an empty value gives the zero SMPPTime
+func ParseSMPPTime(value string) (t SMPPTime, err error) {
+	if value == "" {
+		return
+	}
+	if len(value) != 16 {
+		return t, fmt.Errorf("%w: %q", ErrInvalidTimeFormat, value)
+	}
+	var f [6]int
+	for i := range f {
+		n, err := strconv.ParseUint(value[i*2:i*2+2], 10, 8)
+		if err != nil {
+			return t, fmt.Errorf("%w: %q", ErrInvalidTimeFormat, value)
+		}
+		f[i] = int(n)
+	}
+	for _, c := range value[12:15] {
+		if c < '0' || c > '9' {
+			return t, fmt.Errorf("%w: %q", ErrInvalidTimeFormat, value)
+		}
+	}
+	tenths := int(value[12] - '0')
+	quarters := int(value[13]-'0')*10 + int(value[14]-'0')
+
+	switch value[15] {
+	case 'R':
+		if tenths != 0 || quarters != 0 {
+			return t, fmt.Errorf("%w: %q", ErrInvalidTimeFormat, value)
+		}
+		return SMPPTime{Relative: true, Years: f[0], Months: f[1], Days: f[2], Hours: f[3], Minutes: f[4], Seconds: f[5]}, nil
+	case '+', '-':
+		if quarters > 48 || f[1] < 1 || f[1] > 12 || f[2] < 1 || f[2] > 31 || f[3] > 23 || f[4] > 59 || f[5] > 59 {
+			return t, fmt.Errorf("%w: %q", ErrInvalidTimeFormat, value)
+		}
+		offset := quarters * 15 * 60
+		if value[15] == '-' {
+			offset = -offset
+		}
+		loc := time.FixedZone("", offset)
+		abs := time.Date(2000+f[0], time.Month(f[1]), f[2], f[3], f[4], f[5], tenths*int(time.Second/10), loc)
+		if abs.Day() != f[2] {
+			// e.g. 31st of a 30 day month
+			return t, fmt.Errorf("%w: %q", ErrInvalidTimeFormat, value)
+		}
+		return SMPPTime{Absolute: abs}, nil
+	}
+	return t, fmt.Errorf("%w: %q", ErrInvalidTimeFormat, value)
+}
+
+// IsZero ...
+func (t SMPPTime) IsZero() bool {
+	return t == SMPPTime{}
+}
+
+// String formats the time, an absolute time whose offset isn't a multiple of 15 minutes is sent in UTC
+func (t SMPPTime) String() string {
+	if t.Relative {
+		return fmt.Sprintf("%02d%02d%02d%02d%02d%02d000R", t.Years, t.Months, t.Days, t.Hours, t.Minutes, t.Seconds)
+	}
+	if t.Absolute.IsZero() {
+		return ""
+	}
+	abs := t.Absolute
+	_, offset := abs.Zone()
+	if offset%(15*60) != 0 {
+		abs, offset = abs.UTC(), 0
+	}
+	sign := '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	return fmt.Sprintf("%s%d%02d%c", abs.Format("060102150405"), abs.Nanosecond()/int(time.Second/10), offset/(15*60), sign)
+}
+
+// Time returns the absolute time, a relative time is added to from
+func (t SMPPTime) Time(from time.Time) time.Time {
+	if !t.Relative {
+		return t.Absolute
+	}
+	return from.AddDate(t.Years, t.Months, t.Days).
+		Add(time.Duration(t.Hours)*time.Hour + time.Duration(t.Minutes)*time.Minute + time.Duration(t.Seconds)*time.Second)
+}
+
+// Duration returns the time left from now
+func (t SMPPTime) Duration() time.Duration {
+	now := time.Now()
+	return t.Time(now).Sub(now)
+}
+
+// ValidateTimes checks schedule_delivery_time and validity_period of a request,
+// see SMPP v5, section 4.7.23 (131p)
+func ValidateTimes(schedule, validity string) CommandStatus {
+	if _, err := ParseSMPPTime(schedule); err != nil {
+		return ESME_RINVSCHED
+	}
+	if _, err := ParseSMPPTime(validity); err != nil {
+		return ESME_RINVEXPIRY
+	}
+	return ESME_ROK
+}
@@ -0,0 +1,31 @@
+package pdu
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewRelativeTimeCarry(t *testing.T) {
+	const day = 24 * time.Hour
+	for _, tt := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{99*day + time.Second, "000099000001000R"},
+		{100 * day, "000310000000000R"},
+		{99 * 30 * day, "009900000000000R"},
+		{100 * 30 * day, "080400000000000R"},
+		{1200 * 30 * day, "990000000000000R"},
+		{1300 * 30 * day, "990000000000000R"},
+		{-time.Hour, "000000000000000R"},
+	} {
+		rel := NewRelativeTime(tt.d)
+		if got := rel.String(); got != tt.want {
+			t.Errorf("%v: got %s, want %s", tt.d, got, tt.want)
+		}
+		parsed, err := ParseSMPPTime(rel.String())
+		if err != nil || parsed != rel {
+			t.Errorf("%v: parsed %+v, %v, want %+v", tt.d, parsed, err, rel)
+		}
+	}
+}
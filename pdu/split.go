@@ -0,0 +1,235 @@
+package pdu
+
+import (
+	"sync/atomic"
+
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+// SplitMode selects how the parts of a long message are tied together
+type SplitMode byte
+
+const (
+	SplitUDH     SplitMode = iota // concatenation UDH in short_message, UDHI set
+	SplitSAR                      // sar_msg_ref_num, sar_total_segments and sar_segment_seqnum TLVs
+	SplitPayload                  // a single submit_sm with the whole text in message_payload
+)
+
+// SplitOptions ...
+type SplitOptions struct {
+	Mode SplitMode
+
+	// DataCoding forces the coding, nil picks it with coding.BestNationalCoding
+	DataCoding *coding.DataCoding
+	// NoGSM7 excludes GSM7 when the coding is picked
+	NoGSM7 bool
+	// Languages are the national language tables GSM7 may use, see coding.BestNationalTables
+	Languages []coding.Language
+
+	// Reference ties the parts together, zero takes the next one of a package counter.
+	// The 8-bit IE takes references up to 0xFF, SplitSubmitSM fails with ErrInvalidReference above.
+	Reference uint16
+	// Wide uses the 16-bit reference IE 0x08 instead of the 8-bit IE 0x00
+	Wide bool
+}
+
+var references uint32
+
+// nextReference returns a non-zero concatenation reference
+func nextReference(wide bool) uint16 {
+	for {
+		ref := uint16(atomic.AddUint32(&references, 1))
+		if !wide {
+			ref &= 0xFF
+		}
+		if ref != 0 {
+			return ref
+		}
+	}
+}
+
+// SplitSubmitSM encodes text and cuts it into as many copies of template as needed.
+// Parts are cut at character boundaries, GSM7 escapes and UTF-16 surrogate pairs stay whole.
+func SplitSubmitSM(template *SubmitSM, text string, opts SplitOptions) ([]*SubmitSM, error) {
+	dataCoding, tables := coding.GSM7BitCoding, coding.NationalTables{}
+	if opts.DataCoding != nil {
+		dataCoding = *opts.DataCoding
+	} else {
+		dataCoding, tables = coding.BestNationalCoding(text, !opts.NoGSM7, opts.Languages...)
+	}
+	gsm7 := isGSM7(dataCoding)
+
+	encode := func(s string) []byte {
+		if gsm7 {
+			return tables.Encode(s)
+		}
+		return EncodeMessage(s, dataCoding)
+	}
+	// the IEs of the template and the national language tables go into every part
+	udh := func() UserDataHeader {
+		h := append(UserDataHeader{}, template.ShortMessage.UDHeader...)
+		if gsm7 {
+			h.SetNationalTables(tables)
+		}
+		return h
+	}
+
+	if opts.Mode == SplitPayload {
+		return payloadSubmitSM(template, dataCoding, udh(), encode(text))
+	}
+
+	var udhLength int
+	if h := udh(); len(h) > 0 {
+		udhLength = h.Len()
+	}
+	segments := split(text, dataCoding, tables, udhLength)
+	if len(segments) > 1 && opts.Mode == SplitUDH {
+		concat := 5
+		if opts.Wide {
+			concat++
+		}
+		if udhLength == 0 {
+			concat++ // UDHL
+		}
+		segments = split(text, dataCoding, tables, udhLength+concat)
+	}
+	if len(segments) == 0 {
+		segments = []string{""}
+	}
+	chunks := make([][]byte, len(segments))
+	for i, segment := range segments {
+		chunks[i] = encode(segment)
+	}
+	return concatenate(template, dataCoding, udh(), chunks, opts)
+}
+
+// SplitBinarySubmitSM cuts data into as many copies of template as needed, the template UDH
+// (e.g. application ports) goes into every part. data_coding is 8-bit unless opts.DataCoding is set.
+func SplitBinarySubmitSM(template *SubmitSM, data []byte, opts SplitOptions) ([]*SubmitSM, error) {
+	dataCoding := coding.OctetCoding4
+	if opts.DataCoding != nil {
+		dataCoding = *opts.DataCoding
+	}
+	h := append(UserDataHeader{}, template.ShortMessage.UDHeader...)
+
+	if opts.Mode == SplitPayload {
+		return payloadSubmitSM(template, dataCoding, h, data)
+	}
+
+	var udhLength int
+	if len(h) > 0 {
+		udhLength = h.Len()
+	}
+	size := coding.MaxOctets - udhLength
+	if len(data) > size && opts.Mode == SplitUDH {
+		size -= 5
+		if opts.Wide {
+			size--
+		}
+		if udhLength == 0 {
+			size-- // UDHL
+		}
+	}
+	if size <= 0 {
+		return nil, ErrDataTooLarge
+	}
+	var chunks [][]byte
+	for len(data) > size {
+		chunks = append(chunks, data[:size:size])
+		data = data[size:]
+	}
+	chunks = append(chunks, data)
+	return concatenate(template, dataCoding, h, chunks, opts)
+}
+
+// payloadSubmitSM copies template with the UDH and data in message_payload
+func payloadSubmitSM(template *SubmitSM, dataCoding coding.DataCoding, udh UserDataHeader, data []byte) ([]*SubmitSM, error) {
+	p := cloneSubmitSM(template)
+	p.ShortMessage.DataCoding = dataCoding
+	var payload []byte
+	if len(udh) > 0 {
+		var err error
+		if payload, err = udh.appendTo(nil); err != nil {
+			return nil, err
+		}
+		p.ESMClass.UDHIndicator = true
+	}
+	payload = append(payload, data...)
+	if len(payload) > 0xFFFE {
+		return nil, ErrDataTooLarge
+	}
+	p.Tags.SetMessagePayload(payload)
+	return []*SubmitSM{p}, nil
+}
+
+// concatenate makes a copy of template for every chunk and ties them together
+func concatenate(template *SubmitSM, dataCoding coding.DataCoding, udh UserDataHeader, chunks [][]byte, opts SplitOptions) ([]*SubmitSM, error) {
+	if len(chunks) > 0xFF {
+		return nil, ErrItemTooMany
+	}
+
+	ref := opts.Reference
+	if opts.Mode == SplitUDH && !opts.Wide && ref > 0xFF {
+		return nil, ErrInvalidReference
+	}
+	if ref == 0 {
+		ref = nextReference(opts.Wide)
+	}
+
+	parts := make([]*SubmitSM, 0, len(chunks))
+	for i, chunk := range chunks {
+		p := cloneSubmitSM(template)
+		p.ShortMessage.DataCoding = dataCoding
+		p.ShortMessage.Message = chunk
+
+		h := append(UserDataHeader{}, udh...)
+		if len(chunks) > 1 {
+			total, seq := byte(len(chunks)), byte(i+1)
+			switch {
+			case opts.Mode == SplitSAR:
+				p.Tags.SetSarMsgRefNum(ref)
+				p.Tags.SetSarTotalSegments(total)
+				p.Tags.SetSarSegmentSeqnum(seq)
+			case opts.Wide:
+				h.Remove(IEConcatenated8)
+				h.Set(InformationElement{IEConcatenated16, []byte{byte(ref >> 8), byte(ref), total, seq}})
+			default:
+				h.Remove(IEConcatenated16)
+				h.Set(InformationElement{IEConcatenated8, []byte{byte(ref), total, seq}})
+			}
+		}
+		if len(h) > 0 {
+			p.ShortMessage.UDHeader = h
+			p.ESMClass.UDHIndicator = true
+		}
+		parts = append(parts, p)
+	}
+	return parts, nil
+}
+
+// split cuts text into parts that fit next to a UDH of udhLength octets
+func split(text string, dataCoding coding.DataCoding, tables coding.NationalTables, udhLength int) []string {
+	splitter := dataCoding.Splitter()
+	if isGSM7(dataCoding) {
+		splitter = tables.Splitter()
+	} else if splitter == nil {
+		splitter = coding.OctetCoding.Splitter()
+	}
+	return splitter.Split(text, coding.MaxOctets, udhLength)
+}
+
+// cloneSubmitSM copies template without its header, UDH and TLVs are copied deeply
+func cloneSubmitSM(template *SubmitSM) *SubmitSM {
+	p := *template
+	p.Header = Header{}
+	p.ShortMessage.UDHeader = nil
+	p.ShortMessage.Message = nil
+	p.ShortMessage.Packed = false
+	if template.Tags != nil {
+		p.Tags = make(Tags, len(template.Tags))
+		for tag, data := range template.Tags {
+			p.Tags[tag] = data
+		}
+	}
+	return &p
+}
@@ -0,0 +1,91 @@
+package pdu
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+func TestSplitPayload(t *testing.T) {
+	template := testSubmitSM()
+	template.ShortMessage.UDHeader = UserDataHeader{PortAddress{Dest: VCardPort, Wide: true}.IE()}
+	udh, _ := template.ShortMessage.UDHeader.appendTo(nil)
+	text := strings.Repeat("a", 300)
+
+	textParts, err := SplitSubmitSM(template, text, SplitOptions{Mode: SplitPayload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	binaryParts, err := SplitBinarySubmitSM(template, []byte(text), SplitOptions{Mode: SplitPayload})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, parts := range [][]*SubmitSM{textParts, binaryParts} {
+		if len(parts) != 1 {
+			t.Fatalf("%d parts, want 1", len(parts))
+		}
+		p := parts[0]
+		payload, _ := p.Tags.MessagePayload()
+		if !p.ESMClass.UDHIndicator || !bytes.Equal(payload, append(udh, text...)) {
+			t.Errorf("UDHI %v, message_payload %x", p.ESMClass.UDHIndicator, payload)
+		}
+		p.Header.Sequence = 1
+		decoded, _, perr := DecodePDU(encode(t, p))
+		if perr != nil {
+			t.Fatal(perr)
+		}
+		if m := decoded.(*SubmitSM).ShortMessage; m.UDHeader != nil || len(m.Message) != 0 {
+			t.Errorf("short_message %+v, want none", m)
+		}
+	}
+}
+
+func TestSplitBoundaries(t *testing.T) {
+	ucs2 := coding.UCS2Coding
+	for _, tt := range []struct {
+		name       string
+		mode       SplitMode
+		dataCoding *coding.DataCoding
+		head, tail string // head fills the first part but a septet or an octet pair
+	}{
+		// 153 septets next to the concatenation UDH, 160 without
+		{"udh gsm7 escape", SplitUDH, nil, strings.Repeat("a", 152), "€" + strings.Repeat("b", 10)},
+		{"sar gsm7 escape", SplitSAR, nil, strings.Repeat("a", 159), "{" + strings.Repeat("b", 10)},
+		// 67 UTF-16 units next to the concatenation UDH, 70 without
+		{"udh surrogate pair", SplitUDH, &ucs2, strings.Repeat("a", 66), "😀" + strings.Repeat("b", 10)},
+		{"sar surrogate pair", SplitSAR, &ucs2, strings.Repeat("a", 69), "😀" + strings.Repeat("b", 10)},
+	} {
+		parts, err := SplitSubmitSM(testSubmitSM(), tt.head+tt.tail, SplitOptions{Mode: tt.mode, DataCoding: tt.dataCoding, Reference: 7})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if len(parts) != 2 {
+			t.Fatalf("%s: %d parts, want 2", tt.name, len(parts))
+		}
+		for i, want := range []string{tt.head, tt.tail} {
+			m := parts[i].ShortMessage
+			if got := m.Decode(); got != want {
+				t.Errorf("%s: part %d is %q, want %q", tt.name, i+1, got, want)
+			}
+			hasUDH := m.UDHeader != nil
+			ref, hasSAR := parts[i].Tags.SarMsgRefNum()
+			if hasUDH != (tt.mode == SplitUDH) || hasSAR != (tt.mode == SplitSAR) || hasSAR && ref != 7 {
+				t.Errorf("%s: part %d UDH %v, sar_msg_ref_num %d %v", tt.name, i+1, m.UDHeader, ref, hasSAR)
+			}
+		}
+	}
+}
+
+func TestSplitReference(t *testing.T) {
+	text := strings.Repeat("a", 200)
+	if _, err := SplitSubmitSM(testSubmitSM(), text, SplitOptions{Reference: 0x100}); err != ErrInvalidReference {
+		t.Errorf("8-bit reference 0x100: got %v, want ErrInvalidReference", err)
+	}
+	for _, opts := range []SplitOptions{{Reference: 0x100, Wide: true}, {Reference: 0x100, Mode: SplitSAR}} {
+		if _, err := SplitSubmitSM(testSubmitSM(), text, opts); err != nil {
+			t.Errorf("%+v: %v", opts, err)
+		}
+	}
+}
@@ -0,0 +1,240 @@
+package pdu
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// TLV tags see SMPP v5, section 4.8.4 (135p)
+const (
+	TagDestAddrSubunit            uint16 = 0x0005 // dest_addr_subunit
+	TagDestNetworkType            uint16 = 0x0006 // dest_network_type
+	TagDestBearerType             uint16 = 0x0007 // dest_bearer_type
+	TagDestTelematicsID           uint16 = 0x0008 // dest_telematics_id
+	TagSourceAddrSubunit          uint16 = 0x000D // source_addr_subunit
+	TagSourceNetworkType          uint16 = 0x000E // source_network_type
+	TagSourceBearerType           uint16 = 0x000F // source_bearer_type
+	TagSourceTelematicsID         uint16 = 0x0010 // source_telematics_id
+	TagQosTimeToLive              uint16 = 0x0017 // qos_time_to_live
+	TagPayloadType                uint16 = 0x0019 // payload_type
+	TagAdditionalStatusInfoText   uint16 = 0x001D // additional_status_info_text
+	TagReceiptedMessageID         uint16 = 0x001E // receipted_message_id
+	TagMsMsgWaitFacilities        uint16 = 0x0030 // ms_msg_wait_facilities
+	TagPrivacyIndicator           uint16 = 0x0201 // privacy_indicator
+	TagSourceSubaddress           uint16 = 0x0202 // source_subaddress
+	TagDestSubaddress             uint16 = 0x0203 // dest_subaddress
+	TagUserMessageReference       uint16 = 0x0204 // user_message_reference
+	TagUserResponseCode           uint16 = 0x0205 // user_response_code
+	TagSourcePort                 uint16 = 0x020A // source_port
+	TagDestinationPort            uint16 = 0x020B // destination_port
+	TagSarMsgRefNum               uint16 = 0x020C // sar_msg_ref_num
+	TagLanguageIndicator          uint16 = 0x020D // language_indicator
+	TagSarTotalSegments           uint16 = 0x020E // sar_total_segments
+	TagSarSegmentSeqnum           uint16 = 0x020F // sar_segment_seqnum
+	TagSCInterfaceVersion         uint16 = 0x0210 // sc_interface_version
+	TagCallbackNumPresInd         uint16 = 0x0302 // callback_num_pres_ind
+	TagCallbackNumAtag            uint16 = 0x0303 // callback_num_atag
+	TagNumberOfMessages           uint16 = 0x0304 // number_of_messages
+	TagCallbackNum                uint16 = 0x0381 // callback_num
+	TagDpfResult                  uint16 = 0x0420 // dpf_result
+	TagSetDpf                     uint16 = 0x0421 // set_dpf
+	TagMsAvailabilityStatus       uint16 = 0x0422 // ms_availability_status
+	TagNetworkErrorCode           uint16 = 0x0423 // network_error_code
+	TagMessagePayload             uint16 = 0x0424 // message_payload
+	TagDeliveryFailureReason      uint16 = 0x0425 // delivery_failure_reason
+	TagMoreMessagesToSend         uint16 = 0x0426 // more_messages_to_send
+	TagMessageState               uint16 = 0x0427 // message_state
+	TagCongestionState            uint16 = 0x0428 // congestion_state
+	TagUssdServiceOp              uint16 = 0x0501 // ussd_service_op
+	TagBroadcastChannelIndicator  uint16 = 0x0600 // broadcast_channel_indicator
+	TagBroadcastContentType       uint16 = 0x0601 // broadcast_content_type
+	TagBroadcastContentTypeInfo   uint16 = 0x0602 // broadcast_content_type_info
+	TagBroadcastMessageClass      uint16 = 0x0603 // broadcast_message_class
+	TagBroadcastRepNum            uint16 = 0x0604 // broadcast_rep_num
+	TagBroadcastFrequencyInterval uint16 = 0x0605 // broadcast_frequency_interval
+	TagBroadcastAreaIdentifier    uint16 = 0x0606 // broadcast_area_identifier
+	TagBroadcastErrorStatus       uint16 = 0x0607 // broadcast_error_status
+	TagBroadcastAreaSuccess       uint16 = 0x0608 // broadcast_area_success
+	TagBroadcastEndTime           uint16 = 0x0609 // broadcast_end_time
+	TagBroadcastServiceGroup      uint16 = 0x060A // broadcast_service_group
+	TagBillingIdentification      uint16 = 0x060B // billing_identification
+	TagSourceNetworkID            uint16 = 0x060D // source_network_id
+	TagDestNetworkID              uint16 = 0x060E // dest_network_id
+	TagSourceNodeID               uint16 = 0x060F // source_node_id
+	TagDestNodeID                 uint16 = 0x0610 // dest_node_id
+	TagDestAddrNpResolution       uint16 = 0x0611 // dest_addr_np_resolution
+	TagDestAddrNpInformation      uint16 = 0x0612 // dest_addr_np_information
+	TagDestAddrNpCountry          uint16 = 0x0613 // dest_addr_np_country
+	TagDisplayTime                uint16 = 0x1201 // display_time
+	TagSmsSignal                  uint16 = 0x1203 // sms_signal
+	TagMsValidity                 uint16 = 0x1204 // ms_validity
+	TagAlertOnMessageDelivery     uint16 = 0x130C // alert_on_message_delivery
+	TagItsReplyType               uint16 = 0x1380 // its_reply_type
+	TagItsSessionInfo             uint16 = 0x1383 // its_session_info
+)
+
+// TagType is the wire type of a TLV value
+type TagType byte
+
+const (
+	TagTypeOctets TagType = iota
+	TagTypeUint8
+	TagTypeUint16
+	TagTypeUint32
+	TagTypeCString
+)
+
+// TagDefinition describes a TLV and the bounds of its value length
+type TagDefinition struct {
+	Tag       uint16
+	Name      string
+	Type      TagType
+	MinLength int
+	MaxLength int
+
+	// Validator checks the value beyond its length, optional
+	Validator func(data []byte) error
+}
+
+// TagRegistry holds the standard SMPP 3.4/5.0 TLVs by tag
+var TagRegistry = map[uint16]TagDefinition{
+	TagDestAddrSubunit:            {TagDestAddrSubunit, "dest_addr_subunit", TagTypeUint8, 1, 1, nil},
+	TagDestNetworkType:            {TagDestNetworkType, "dest_network_type", TagTypeUint8, 1, 1, nil},
+	TagDestBearerType:             {TagDestBearerType, "dest_bearer_type", TagTypeUint8, 1, 1, nil},
+	TagDestTelematicsID:           {TagDestTelematicsID, "dest_telematics_id", TagTypeUint16, 2, 2, nil},
+	TagSourceAddrSubunit:          {TagSourceAddrSubunit, "source_addr_subunit", TagTypeUint8, 1, 1, nil},
+	TagSourceNetworkType:          {TagSourceNetworkType, "source_network_type", TagTypeUint8, 1, 1, nil},
+	TagSourceBearerType:           {TagSourceBearerType, "source_bearer_type", TagTypeUint8, 1, 1, nil},
+	TagSourceTelematicsID:         {TagSourceTelematicsID, "source_telematics_id", TagTypeUint8, 1, 1, nil},
+	TagQosTimeToLive:              {TagQosTimeToLive, "qos_time_to_live", TagTypeUint32, 4, 4, nil},
+	TagPayloadType:                {TagPayloadType, "payload_type", TagTypeUint8, 1, 1, nil},
+	TagAdditionalStatusInfoText:   {TagAdditionalStatusInfoText, "additional_status_info_text", TagTypeCString, 1, 256, nil},
+	TagReceiptedMessageID:         {TagReceiptedMessageID, "receipted_message_id", TagTypeCString, 1, 65, nil},
+	TagMsMsgWaitFacilities:        {TagMsMsgWaitFacilities, "ms_msg_wait_facilities", TagTypeUint8, 1, 1, nil},
+	TagPrivacyIndicator:           {TagPrivacyIndicator, "privacy_indicator", TagTypeUint8, 1, 1, nil},
+	TagSourceSubaddress:           {TagSourceSubaddress, "source_subaddress", TagTypeOctets, 2, 23, nil},
+	TagDestSubaddress:             {TagDestSubaddress, "dest_subaddress", TagTypeOctets, 2, 23, nil},
+	TagUserMessageReference:       {TagUserMessageReference, "user_message_reference", TagTypeUint16, 2, 2, nil},
+	TagUserResponseCode:           {TagUserResponseCode, "user_response_code", TagTypeUint8, 1, 1, nil},
+	TagSourcePort:                 {TagSourcePort, "source_port", TagTypeUint16, 2, 2, nil},
+	TagDestinationPort:            {TagDestinationPort, "destination_port", TagTypeUint16, 2, 2, nil},
+	TagSarMsgRefNum:               {TagSarMsgRefNum, "sar_msg_ref_num", TagTypeUint16, 2, 2, nil},
+	TagLanguageIndicator:          {TagLanguageIndicator, "language_indicator", TagTypeUint8, 1, 1, nil},
+	TagSarTotalSegments:           {TagSarTotalSegments, "sar_total_segments", TagTypeUint8, 1, 1, nil},
+	TagSarSegmentSeqnum:           {TagSarSegmentSeqnum, "sar_segment_seqnum", TagTypeUint8, 1, 1, nil},
+	TagSCInterfaceVersion:         {TagSCInterfaceVersion, "sc_interface_version", TagTypeUint8, 1, 1, nil},
+	TagCallbackNumPresInd:         {TagCallbackNumPresInd, "callback_num_pres_ind", TagTypeUint8, 1, 1, nil},
+	TagCallbackNumAtag:            {TagCallbackNumAtag, "callback_num_atag", TagTypeOctets, 0, 65, nil},
+	TagNumberOfMessages:           {TagNumberOfMessages, "number_of_messages", TagTypeUint8, 1, 1, nil},
+	TagCallbackNum:                {TagCallbackNum, "callback_num", TagTypeOctets, 4, 19, nil},
+	TagDpfResult:                  {TagDpfResult, "dpf_result", TagTypeUint8, 1, 1, nil},
+	TagSetDpf:                     {TagSetDpf, "set_dpf", TagTypeUint8, 1, 1, nil},
+	TagMsAvailabilityStatus:       {TagMsAvailabilityStatus, "ms_availability_status", TagTypeUint8, 1, 1, nil},
+	TagNetworkErrorCode:           {TagNetworkErrorCode, "network_error_code", TagTypeOctets, 3, 3, nil},
+	TagMessagePayload:             {TagMessagePayload, "message_payload", TagTypeOctets, 0, 0xFFFE, nil},
+	TagDeliveryFailureReason:      {TagDeliveryFailureReason, "delivery_failure_reason", TagTypeUint8, 1, 1, nil},
+	TagMoreMessagesToSend:         {TagMoreMessagesToSend, "more_messages_to_send", TagTypeUint8, 1, 1, nil},
+	TagMessageState:               {TagMessageState, "message_state", TagTypeUint8, 1, 1, nil},
+	TagCongestionState:            {TagCongestionState, "congestion_state", TagTypeUint8, 1, 1, nil},
+	TagUssdServiceOp:              {TagUssdServiceOp, "ussd_service_op", TagTypeUint8, 1, 1, nil},
+	TagBroadcastChannelIndicator:  {TagBroadcastChannelIndicator, "broadcast_channel_indicator", TagTypeUint8, 1, 1, nil},
+	TagBroadcastContentType:       {TagBroadcastContentType, "broadcast_content_type", TagTypeOctets, 3, 3, nil},
+	TagBroadcastContentTypeInfo:   {TagBroadcastContentTypeInfo, "broadcast_content_type_info", TagTypeOctets, 0, 255, nil},
+	TagBroadcastMessageClass:      {TagBroadcastMessageClass, "broadcast_message_class", TagTypeUint8, 1, 1, nil},
+	TagBroadcastRepNum:            {TagBroadcastRepNum, "broadcast_rep_num", TagTypeUint16, 2, 2, nil},
+	TagBroadcastFrequencyInterval: {TagBroadcastFrequencyInterval, "broadcast_frequency_interval", TagTypeOctets, 3, 3, nil},
+	TagBroadcastAreaIdentifier:    {TagBroadcastAreaIdentifier, "broadcast_area_identifier", TagTypeOctets, 0, 100, nil},
+	TagBroadcastErrorStatus:       {TagBroadcastErrorStatus, "broadcast_error_status", TagTypeUint32, 4, 4, nil},
+	TagBroadcastAreaSuccess:       {TagBroadcastAreaSuccess, "broadcast_area_success", TagTypeUint8, 1, 1, nil},
+	TagBroadcastEndTime:           {TagBroadcastEndTime, "broadcast_end_time", TagTypeCString, 17, 17, nil},
+	TagBroadcastServiceGroup:      {TagBroadcastServiceGroup, "broadcast_service_group", TagTypeOctets, 0, 255, nil},
+	TagBillingIdentification:      {TagBillingIdentification, "billing_identification", TagTypeOctets, 0, 1024, nil},
+	TagSourceNetworkID:            {TagSourceNetworkID, "source_network_id", TagTypeCString, 7, 66, nil},
+	TagDestNetworkID:              {TagDestNetworkID, "dest_network_id", TagTypeCString, 7, 66, nil},
+	TagSourceNodeID:               {TagSourceNodeID, "source_node_id", TagTypeOctets, 6, 6, nil},
+	TagDestNodeID:                 {TagDestNodeID, "dest_node_id", TagTypeOctets, 6, 6, nil},
+	TagDestAddrNpResolution:       {TagDestAddrNpResolution, "dest_addr_np_resolution", TagTypeUint8, 1, 1, nil},
+	TagDestAddrNpInformation:      {TagDestAddrNpInformation, "dest_addr_np_information", TagTypeOctets, 10, 10, nil},
+	TagDestAddrNpCountry:          {TagDestAddrNpCountry, "dest_addr_np_country", TagTypeOctets, 5, 5, nil},
+	TagDisplayTime:                {TagDisplayTime, "display_time", TagTypeUint8, 1, 1, nil},
+	TagSmsSignal:                  {TagSmsSignal, "sms_signal", TagTypeUint16, 2, 2, nil},
+	TagMsValidity:                 {TagMsValidity, "ms_validity", TagTypeOctets, 1, 4, nil},
+	TagAlertOnMessageDelivery:     {TagAlertOnMessageDelivery, "alert_on_message_delivery", TagTypeOctets, 0, 1, nil},
+	TagItsReplyType:               {TagItsReplyType, "its_reply_type", TagTypeUint8, 1, 1, nil},
+	TagItsSessionInfo:             {TagItsSessionInfo, "its_session_info", TagTypeOctets, 2, 2, nil},
+}
+
+// Validate checks the value length and, for C-Octet strings, the NULL terminator
+func (d TagDefinition) Validate(data []byte) error {
+	if len(data) < d.MinLength || len(data) > d.MaxLength {
+		return ESME_RINVPARLEN
+	}
+	if d.Type == TagTypeCString && (len(data) == 0 || data[len(data)-1] != 0) {
+		return ESME_RINVOPTPARAMVAL
+	}
+	if d.Validator != nil {
+		return d.Validator(data)
+	}
+	return nil
+}
+
+// decodeValue converts a valid value to its Go type, octets and values that don't fit the type are hex encoded
+func (d TagDefinition) decodeValue(data []byte) interface{} {
+	switch {
+	case d.Type == TagTypeUint8 && len(data) == 1:
+		return data[0]
+	case d.Type == TagTypeUint16 && len(data) == 2:
+		return binary.BigEndian.Uint16(data)
+	case d.Type == TagTypeUint32 && len(data) == 4:
+		return binary.BigEndian.Uint32(data)
+	case d.Type == TagTypeCString && len(data) > 0:
+		return string(data[:len(data)-1])
+	}
+	return hex.EncodeToString(data)
+}
+
+// encodeValue is the inverse of decodeValue for a JSON value
+func (d TagDefinition) encodeValue(raw json.RawMessage) ([]byte, error) {
+	var data []byte
+	switch d.Type {
+	case TagTypeUint8, TagTypeUint16, TagTypeUint32:
+		var value uint32
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		switch {
+		case d.Type == TagTypeUint8 && value > 0xFF, d.Type == TagTypeUint16 && value > 0xFFFF:
+			return nil, ESME_RINVOPTPARAMVAL
+		}
+		switch d.Type {
+		case TagTypeUint8:
+			data = []byte{byte(value)}
+		case TagTypeUint16:
+			data = binary.BigEndian.AppendUint16(nil, uint16(value))
+		default:
+			data = binary.BigEndian.AppendUint32(nil, value)
+		}
+	case TagTypeCString:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		data = append([]byte(value), 0)
+	default:
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			return nil, err
+		}
+		var err error
+		if data, err = hex.DecodeString(value); err != nil {
+			return nil, err
+		}
+	}
+	if d.Name != "" {
+		if err := d.Validate(data); err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
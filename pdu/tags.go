@@ -3,9 +3,13 @@ package pdu
 import (
 	"bytes"
 	"encoding/binary"
-	"errors"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
 	"io"
 	"sort"
+	"strconv"
+	"strings"
 )
 
 // Tags ...
@@ -19,7 +23,9 @@ func (t *Tags) ReadFrom(r io.Reader) (n int64, err error) {
 		err = binary.Read(r, binary.BigEndian, values[:])
 		if err == nil {
 			data = make([]byte, values[1])
-			_, err = r.Read(data)
+			if _, err = io.ReadFull(r, data); err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
 		}
 		if err == nil {
 			tags[values[0]] = data
@@ -45,7 +51,7 @@ func (t Tags) WriteTo(w io.Writer) (n int64, err error) {
 		keys = append(keys, tag)
 	}
 	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
-	err = errors.New("InvalidTagLength")
+	err = ErrInvalidTagLength
 	for _, tag := range keys {
 		data := t[tag]
 		length := len(data)
@@ -62,7 +68,330 @@ func (t Tags) WriteTo(w io.Writer) (n int64, err error) {
 	return buf.WriteTo(w)
 }
 
-// MarshalJSON ...
-// func (t Tags) MarshalJSON() (data []byte, err error) {
-// 	return json.Marshal(t.String())
-// }
+// TagField is a TLV decoded according to its definition
+type TagField struct {
+	Tag   uint16
+	Name  string // the registered name or the tag in hex
+	Value interface{}
+}
+
+// Fields decodes the TLVs known to profile, sorted by tag. Unknown or
+// malformed values are returned as a hex string.
+func (t Tags) Fields(profile *VendorProfile) []TagField {
+	keys := make([]uint16, 0, len(t))
+	for tag := range t {
+		keys = append(keys, tag)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+
+	fields := make([]TagField, 0, len(keys))
+	for _, tag := range keys {
+		data := t[tag]
+		field := TagField{Tag: tag, Name: fmt.Sprintf("0x%04X", tag), Value: hex.EncodeToString(data)}
+		if def, ok := profile.Lookup(tag); ok {
+			field.Name = def.Name
+			if def.Validate(data) == nil {
+				field.Value = def.decodeValue(data)
+			}
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// Format renders the TLVs for debug output, e.g. "receipted_message_id=1A2B message_state=2"
+func (t Tags) Format(profile *VendorProfile) string {
+	var sb strings.Builder
+	for i, field := range t.Fields(profile) {
+		if i > 0 {
+			sb.WriteByte(' ')
+		}
+		_, _ = fmt.Fprintf(&sb, "%s=%v", field.Name, field.Value)
+	}
+	return sb.String()
+}
+
+// String formats the TLVs with the default vendor profile
+func (t Tags) String() string {
+	return t.Format(DefaultVendorProfile())
+}
+
+// MarshalJSON encodes the TLVs with the default vendor profile, see EncodeJSON
+func (t Tags) MarshalJSON() ([]byte, error) {
+	return t.EncodeJSON(DefaultVendorProfile())
+}
+
+// EncodeJSON encodes the TLVs as an object keyed by the names profile gives them, see Fields
+func (t Tags) EncodeJSON(profile *VendorProfile) ([]byte, error) {
+	buf := []byte{'{'}
+	for i, field := range t.Fields(profile) {
+		if i > 0 {
+			buf = append(buf, ',')
+		}
+		name, _ := json.Marshal(field.Name)
+		value, err := json.Marshal(field.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf = append(buf, name...)
+		buf = append(buf, ':')
+		buf = append(buf, value...)
+	}
+	return append(buf, '}'), nil
+}
+
+// UnmarshalJSON is the inverse of MarshalJSON
+func (t *Tags) UnmarshalJSON(data []byte) error {
+	return t.DecodeJSON(data, DefaultVendorProfile())
+}
+
+// DecodeJSON is the inverse of EncodeJSON
+func (t *Tags) DecodeJSON(data []byte, profile *VendorProfile) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	tags := make(Tags, len(fields))
+	for name, raw := range fields {
+		def, ok := profile.LookupName(name)
+		if !ok {
+			tag, err := strconv.ParseUint(strings.TrimPrefix(name, "0x"), 16, 16)
+			if err != nil {
+				return fmt.Errorf("%w: %s", ErrInvalidTagName, name)
+			}
+			def = TagDefinition{Tag: uint16(tag), Type: TagTypeOctets}
+		}
+		value, err := def.encodeValue(raw)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		tags[def.Tag] = value
+	}
+	*t = tags
+	return nil
+}
+
+// Uint8 returns the value of a one octet TLV
+func (t Tags) Uint8(tag uint16) (byte, bool) {
+	if data, ok := t[tag]; ok && len(data) == 1 {
+		return data[0], true
+	}
+	return 0, false
+}
+
+// Uint16 returns the value of a two octet TLV
+func (t Tags) Uint16(tag uint16) (uint16, bool) {
+	if data, ok := t[tag]; ok && len(data) == 2 {
+		return binary.BigEndian.Uint16(data), true
+	}
+	return 0, false
+}
+
+// Uint32 returns the value of a four octet TLV
+func (t Tags) Uint32(tag uint16) (uint32, bool) {
+	if data, ok := t[tag]; ok && len(data) == 4 {
+		return binary.BigEndian.Uint32(data), true
+	}
+	return 0, false
+}
+
+// CString returns the value of a C-Octet string TLV without the NULL terminator
+func (t Tags) CString(tag uint16) (string, bool) {
+	data, ok := t[tag]
+	if !ok {
+		return "", false
+	}
+	if i := bytes.IndexByte(data, 0); i >= 0 {
+		data = data[:i]
+	}
+	return string(data), true
+}
+
+// Octets returns the raw value of a TLV
+func (t Tags) Octets(tag uint16) ([]byte, bool) {
+	data, ok := t[tag]
+	return data, ok
+}
+
+// Set stores the raw value of a TLV
+func (t *Tags) Set(tag uint16, data []byte) {
+	if *t == nil {
+		*t = make(Tags)
+	}
+	(*t)[tag] = data
+}
+
+// SetUint8 ...
+func (t *Tags) SetUint8(tag uint16, value byte) {
+	t.Set(tag, []byte{value})
+}
+
+// SetUint16 ...
+func (t *Tags) SetUint16(tag uint16, value uint16) {
+	t.Set(tag, binary.BigEndian.AppendUint16(nil, value))
+}
+
+// SetUint32 ...
+func (t *Tags) SetUint32(tag uint16, value uint32) {
+	t.Set(tag, binary.BigEndian.AppendUint32(nil, value))
+}
+
+// SetCString stores value with a NULL terminator
+func (t *Tags) SetCString(tag uint16, value string) {
+	t.Set(tag, append([]byte(value), 0))
+}
+
+// Validate checks every known TLV against TagRegistry
+func (t Tags) Validate() error {
+	for tag, data := range t {
+		if def, ok := TagRegistry[tag]; ok {
+			if err := def.Validate(data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// NetworkErrorCode see SMPP v5, section 4.8.4.42 (158p)
+type NetworkErrorCode struct {
+	NetworkType byte // 1 = ANSI-136, 2 = IS-95, 3 = GSM, 8 = SMPP
+	ErrorCode   uint16
+}
+
+// ReceiptedMessageID ...
+func (t Tags) ReceiptedMessageID() (string, bool) { return t.CString(TagReceiptedMessageID) }
+
+// SetReceiptedMessageID ...
+func (t *Tags) SetReceiptedMessageID(id string) { t.SetCString(TagReceiptedMessageID, id) }
+
+// MessageState ...
+func (t Tags) MessageState() (MessageState, bool) {
+	state, ok := t.Uint8(TagMessageState)
+	return MessageState(state), ok
+}
+
+// SetMessageState ...
+func (t *Tags) SetMessageState(state MessageState) { t.SetUint8(TagMessageState, byte(state)) }
+
+// NetworkErrorCode ...
+func (t Tags) NetworkErrorCode() (NetworkErrorCode, bool) {
+	if data, ok := t[TagNetworkErrorCode]; ok && len(data) == 3 {
+		return NetworkErrorCode{data[0], binary.BigEndian.Uint16(data[1:])}, true
+	}
+	return NetworkErrorCode{}, false
+}
+
+// SetNetworkErrorCode ...
+func (t *Tags) SetNetworkErrorCode(code NetworkErrorCode) {
+	t.Set(TagNetworkErrorCode, binary.BigEndian.AppendUint16([]byte{code.NetworkType}, code.ErrorCode))
+}
+
+// MessagePayload ...
+func (t Tags) MessagePayload() ([]byte, bool) { return t.Octets(TagMessagePayload) }
+
+// SetMessagePayload ...
+func (t *Tags) SetMessagePayload(payload []byte) { t.Set(TagMessagePayload, payload) }
+
+// SarMsgRefNum ...
+func (t Tags) SarMsgRefNum() (uint16, bool) { return t.Uint16(TagSarMsgRefNum) }
+
+// SetSarMsgRefNum ...
+func (t *Tags) SetSarMsgRefNum(ref uint16) { t.SetUint16(TagSarMsgRefNum, ref) }
+
+// SarTotalSegments ...
+func (t Tags) SarTotalSegments() (byte, bool) { return t.Uint8(TagSarTotalSegments) }
+
+// SetSarTotalSegments ...
+func (t *Tags) SetSarTotalSegments(total byte) { t.SetUint8(TagSarTotalSegments, total) }
+
+// SarSegmentSeqnum ...
+func (t Tags) SarSegmentSeqnum() (byte, bool) { return t.Uint8(TagSarSegmentSeqnum) }
+
+// SetSarSegmentSeqnum ...
+func (t *Tags) SetSarSegmentSeqnum(seq byte) { t.SetUint8(TagSarSegmentSeqnum, seq) }
+
+// UserMessageReference ...
+func (t Tags) UserMessageReference() (uint16, bool) { return t.Uint16(TagUserMessageReference) }
+
+// SetUserMessageReference ...
+func (t *Tags) SetUserMessageReference(ref uint16) { t.SetUint16(TagUserMessageReference, ref) }
+
+// SourcePort ...
+func (t Tags) SourcePort() (uint16, bool) { return t.Uint16(TagSourcePort) }
+
+// SetSourcePort ...
+func (t *Tags) SetSourcePort(port uint16) { t.SetUint16(TagSourcePort, port) }
+
+// DestinationPort ...
+func (t Tags) DestinationPort() (uint16, bool) { return t.Uint16(TagDestinationPort) }
+
+// SetDestinationPort ...
+func (t *Tags) SetDestinationPort(port uint16) { t.SetUint16(TagDestinationPort, port) }
+
+// UssdServiceOp ...
+func (t Tags) UssdServiceOp() (byte, bool) { return t.Uint8(TagUssdServiceOp) }
+
+// SetUssdServiceOp ...
+func (t *Tags) SetUssdServiceOp(op byte) { t.SetUint8(TagUssdServiceOp, op) }
+
+// CongestionState returns the MC load in percent, 0 to 100
+func (t Tags) CongestionState() (byte, bool) { return t.Uint8(TagCongestionState) }
+
+// SetCongestionState ...
+func (t *Tags) SetCongestionState(state byte) { t.SetUint8(TagCongestionState, state) }
+
+// MsMsgWaitFacilities ...
+func (t Tags) MsMsgWaitFacilities() (byte, bool) { return t.Uint8(TagMsMsgWaitFacilities) }
+
+// SetMsMsgWaitFacilities ...
+func (t *Tags) SetMsMsgWaitFacilities(value byte) { t.SetUint8(TagMsMsgWaitFacilities, value) }
+
+// PayloadType ...
+func (t Tags) PayloadType() (byte, bool) { return t.Uint8(TagPayloadType) }
+
+// SetPayloadType ...
+func (t *Tags) SetPayloadType(value byte) { t.SetUint8(TagPayloadType, value) }
+
+// LanguageIndicator ...
+func (t Tags) LanguageIndicator() (byte, bool) { return t.Uint8(TagLanguageIndicator) }
+
+// SetLanguageIndicator ...
+func (t *Tags) SetLanguageIndicator(value byte) { t.SetUint8(TagLanguageIndicator, value) }
+
+// MoreMessagesToSend ...
+func (t Tags) MoreMessagesToSend() (bool, bool) {
+	value, ok := t.Uint8(TagMoreMessagesToSend)
+	return value == 1, ok
+}
+
+// SetMoreMessagesToSend ...
+func (t *Tags) SetMoreMessagesToSend(more bool) {
+	var value byte
+	if more {
+		value = 1
+	}
+	t.SetUint8(TagMoreMessagesToSend, value)
+}
+
+// DeliveryFailureReason ...
+func (t Tags) DeliveryFailureReason() (byte, bool) { return t.Uint8(TagDeliveryFailureReason) }
+
+// SetDeliveryFailureReason ...
+func (t *Tags) SetDeliveryFailureReason(reason byte) { t.SetUint8(TagDeliveryFailureReason, reason) }
+
+// AdditionalStatusInfoText ...
+func (t Tags) AdditionalStatusInfoText() (string, bool) {
+	return t.CString(TagAdditionalStatusInfoText)
+}
+
+// SetAdditionalStatusInfoText ...
+func (t *Tags) SetAdditionalStatusInfoText(text string) {
+	t.SetCString(TagAdditionalStatusInfoText, text)
+}
+
+// QosTimeToLive ...
+func (t Tags) QosTimeToLive() (uint32, bool) { return t.Uint32(TagQosTimeToLive) }
+
+// SetQosTimeToLive ...
+func (t *Tags) SetQosTimeToLive(seconds uint32) { t.SetUint32(TagQosTimeToLive, seconds) }
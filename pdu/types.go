@@ -12,6 +12,8 @@ var Types = map[CommandID]reflect.Type{}
 
 var commandIDNames = map[CommandID]string{}
 
+var typeCommandIDs = map[reflect.Type]CommandID{}
+
 func init() {
 	pduTypes := []interface{}{
 		AlertNotification{}, GenericNACK{}, Outbind{},
@@ -38,6 +40,7 @@ func init() {
 		_parsed, _ = strconv.ParseUint(t.Field(0).Tag.Get(_ID), 16, 32)
 		_id = CommandID(_parsed)
 		Types[_id] = t
+		typeCommandIDs[t] = _id
 		commandIDNames[_id] = toCommandIDName(t.Name())
 	}
 }
@@ -61,3 +64,12 @@ func (c CommandID) String() string {
 	}
 	return fmt.Sprintf("%08X", uint32(c))
 }
+
+// CommandIDOf returns the command_id declared by the PDU type
+func CommandIDOf(packet interface{}) CommandID {
+	t := reflect.TypeOf(packet)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return typeCommandIDs[t]
+}
@@ -1,29 +1,110 @@
 package pdu
 
 import (
-	"bufio"
 	"bytes"
 	"encoding/binary"
-	"errors"
 	"io"
-	"sort"
+
+	"github.com/goldsheva/smpp-lib/coding"
+)
+
+// Information element identifiers see 3GPP TS 23.040, section 9.2.3.24
+const (
+	IEConcatenated8        byte = 0x00
+	IESpecialSMS           byte = 0x01
+	IEPorts8               byte = 0x04
+	IEPorts16              byte = 0x05
+	IEConcatenated16       byte = 0x08
+	IETextFormatting       byte = 0x0A
+	IENationalSingleShift       = coding.IENationalSingleShift
+	IENationalLockingShift      = coding.IENationalLockingShift
+	IECommandPacket        byte = 0x70 // (U)SIM toolkit security header, see 3GPP TS 31.115
+	IEResponsePacket       byte = 0x71
+	IESIMToolkitMax        byte = 0x7F
 )
 
-// UserDataHeader ...
-type UserDataHeader map[byte][]byte
+// InformationElement is one IE of a UDH, unknown IEs are kept as they are
+type InformationElement struct {
+	ID   byte   `json:"id"`
+	Data []byte `json:"data"`
+}
+
+// UserDataHeader is the ordered list of IEs, IEs may repeat. A nil UDH is absent,
+// an empty non-nil one is encoded as a single zero UDHL octet.
+type UserDataHeader []InformationElement
 
 func (h UserDataHeader) Len() (length int) {
 	if h == nil {
 		return 0
 	}
 	length = 1
-	for _, data := range h {
+	for _, ie := range h {
 		length += 2
-		length += len(data)
+		length += len(ie.Data)
 	}
 	return
 }
 
+// Find returns the first IE with the id
+func (h UserDataHeader) Find(id byte) (InformationElement, bool) {
+	for _, ie := range h {
+		if ie.ID == id {
+			return ie, true
+		}
+	}
+	return InformationElement{}, false
+}
+
+// FindAll returns every IE with the id in order
+func (h UserDataHeader) FindAll(id byte) (found []InformationElement) {
+	for _, ie := range h {
+		if ie.ID == id {
+			found = append(found, ie)
+		}
+	}
+	return
+}
+
+// Add appends the IE, repeating any IE with the same id
+func (h *UserDataHeader) Add(ie InformationElement) {
+	if *h == nil {
+		*h = UserDataHeader{}
+	}
+	*h = append(*h, ie)
+}
+
+// Set replaces every IE with the id of ie, in place of the first one
+func (h *UserDataHeader) Set(ie InformationElement) {
+	for i, cur := range *h {
+		if cur.ID == ie.ID {
+			// a new slice, h may share its array with the UDH it was copied from
+			out := make(UserDataHeader, 0, len(*h))
+			out = append(out, (*h)[:i]...)
+			out = append(out, ie)
+			*h = append(out, (*h)[i+1:].without(ie.ID)...)
+			return
+		}
+	}
+	h.Add(ie)
+}
+
+// Remove drops every IE with the id
+func (h *UserDataHeader) Remove(id byte) {
+	if *h != nil {
+		*h = append(UserDataHeader{}, h.without(id)...)
+	}
+}
+
+func (h UserDataHeader) without(id byte) UserDataHeader {
+	out := make(UserDataHeader, 0, len(h))
+	for _, ie := range h {
+		if ie.ID != id {
+			out = append(out, ie)
+		}
+	}
+	return out
+}
+
 // ConcatenatedHeader ...
 type ConcatenatedHeader struct {
 	Reference  uint16 `json:"user_message_reference"`
@@ -38,41 +119,60 @@ func (h ConcatenatedHeader) Len() int {
 	return 6
 }
 
-func (h ConcatenatedHeader) Set(udh UserDataHeader) {
-	var buf bytes.Buffer
-	_ = binary.Write(&buf, binary.BigEndian, h)
-	if data := buf.Bytes(); data[0] == 0 {
-		udh[0x00] = data[1:4]
-	} else {
-		udh[0x08] = data
+// IE returns IE 0x00 for a reference below 0xFF, IE 0x08 otherwise
+func (h ConcatenatedHeader) IE() InformationElement {
+	if h.Reference < 0xFF {
+		return InformationElement{IEConcatenated8, []byte{byte(h.Reference), h.TotalParts, h.Sequence}}
 	}
+	return InformationElement{IEConcatenated16, []byte{byte(h.Reference >> 8), byte(h.Reference), h.TotalParts, h.Sequence}}
 }
 
-// ReadFrom ...
-func (h *UserDataHeader) ReadFrom(r io.Reader) (n int64, err error) {
+func (h ConcatenatedHeader) Set(udh *UserDataHeader) {
+	udh.Remove(IEConcatenated8)
+	udh.Remove(IEConcatenated16)
+	udh.Add(h.IE())
+}
 
-	buf := bufio.NewReader(r)
-	header := make(UserDataHeader)
-	length, err := buf.ReadByte()
-	if err != nil {
-		return
+// DecodeUserDataHeader decodes the UDH at the start of the short message data and returns
+// the octets it takes. Every IE must fit into UDHL, the IE data aliases data.
+func DecodeUserDataHeader(data []byte) (h UserDataHeader, n int, err error) {
+	if len(data) == 0 {
+		return nil, 0, &UDHError{Offset: 0, Err: ErrInvalidUDHLength}
 	}
-	var id byte
-	var data []byte
-	for i := 0; i < int(length); {
-		if id, err = buf.ReadByte(); err == nil {
-			length, err = buf.ReadByte()
-		}
-		if length > 0 {
-			data = make([]byte, length)
-			_, err = buf.Read(data)
+	n = 1 + int(data[0])
+	if n > len(data) {
+		return nil, 0, &UDHError{Offset: 0, Err: ErrInvalidUDHLength}
+	}
+	h = UserDataHeader{}
+	for pos := 1; pos < n; {
+		if pos+2 > n {
+			return nil, 0, &UDHError{Offset: pos, Err: ErrInvalidIELength}
 		}
-		if err == nil {
-			header[id] = data
+		id, size := data[pos], int(data[pos+1])
+		if pos+2+size > n {
+			return nil, 0, &UDHError{Offset: pos, Err: ErrInvalidIELength}
 		}
-		i = buf.Size()
+		h = append(h, InformationElement{ID: id, Data: data[pos+2 : pos+2+size : pos+2+size]})
+		pos += 2 + size
 	}
-	if len(header) > 0 {
+	return h, n, nil
+}
+
+// ReadFrom reads the UDHL and as many octets as it announces
+func (h *UserDataHeader) ReadFrom(r io.Reader) (n int64, err error) {
+	var length [1]byte
+	if _, err = io.ReadFull(r, length[:]); err != nil {
+		return
+	}
+	data := make([]byte, 1+int(length[0]))
+	data[0] = length[0]
+	read, err := io.ReadFull(r, data[1:])
+	n = 1 + int64(read)
+	if err != nil {
+		return
+	}
+	header, _, err := DecodeUserDataHeader(data)
+	if err == nil {
 		*h = header
 	}
 	return
@@ -83,22 +183,16 @@ func (h UserDataHeader) WriteTo(w io.Writer) (n int64, err error) {
 	if h == nil {
 		return
 	}
-	var keys []byte
-	for id := range h {
-		keys = append(keys, id)
-	}
-	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
 	var buf bytes.Buffer
 	buf.WriteByte(0)
-	err = errors.New("DataTooLarge")
-	for _, id := range keys {
-		data := h[id]
-		if len(data) > 0xFF {
+	err = ErrDataTooLarge
+	for _, ie := range h {
+		if len(ie.Data) > 0xFF {
 			return
 		}
-		buf.WriteByte(id)
-		buf.WriteByte(byte(len(data)))
-		buf.Write(data)
+		buf.WriteByte(ie.ID)
+		buf.WriteByte(byte(len(ie.Data)))
+		buf.Write(ie.Data)
 	}
 	data := buf.Bytes()
 	data[0] = byte(len(data)) - 1
@@ -107,17 +201,20 @@ func (h UserDataHeader) WriteTo(w io.Writer) (n int64, err error) {
 
 // ConcatenatedHeader ...
 func (h UserDataHeader) ConcatenatedHeader() *ConcatenatedHeader {
-	if data, ok := h[0x00]; ok {
-		return &ConcatenatedHeader{
-			Reference:  uint16(data[0]),
-			TotalParts: data[1],
-			Sequence:   data[2],
-		}
-	} else if data, ok = h[0x08]; ok {
-		return &ConcatenatedHeader{
-			Reference:  binary.BigEndian.Uint16(data[0:2]),
-			TotalParts: data[2],
-			Sequence:   data[3],
+	for _, ie := range h {
+		switch data := ie.Data; {
+		case ie.ID == IEConcatenated8 && len(data) == 3:
+			return &ConcatenatedHeader{
+				Reference:  uint16(data[0]),
+				TotalParts: data[1],
+				Sequence:   data[2],
+			}
+		case ie.ID == IEConcatenated16 && len(data) == 4:
+			return &ConcatenatedHeader{
+				Reference:  binary.BigEndian.Uint16(data[0:2]),
+				TotalParts: data[2],
+				Sequence:   data[3],
+			}
 		}
 	}
 	return nil
@@ -129,3 +226,161 @@ func ConcatenatedHeaderLen(reference uint16) int {
 	}
 	return 6
 }
+
+// NationalTables returns the national language tables announced by IEs 0x24 and 0x25
+func (h UserDataHeader) NationalTables() (t coding.NationalTables) {
+	if ie, ok := h.Find(IENationalLockingShift); ok && len(ie.Data) == 1 {
+		t.Locking = coding.Language(ie.Data[0])
+	}
+	if ie, ok := h.Find(IENationalSingleShift); ok && len(ie.Data) == 1 {
+		t.Single = coding.Language(ie.Data[0])
+	}
+	return
+}
+
+// SetNationalTables announces the national language tables, the default ones need no IE
+func (h *UserDataHeader) SetNationalTables(t coding.NationalTables) {
+	h.Remove(IENationalLockingShift)
+	h.Remove(IENationalSingleShift)
+	if t.Locking != coding.LanguageDefault {
+		h.Add(InformationElement{IENationalLockingShift, []byte{byte(t.Locking)}})
+	}
+	if t.Single != coding.LanguageDefault {
+		h.Add(InformationElement{IENationalSingleShift, []byte{byte(t.Single)}})
+	}
+}
+
+// PortAddress see 3GPP TS 23.040, section 9.2.3.24.3-4
+type PortAddress struct {
+	Dest uint16
+	Src  uint16
+	// Wide uses the 16-bit IE 0x05, it is implied by ports above 255
+	Wide bool
+}
+
+// IE ...
+func (p PortAddress) IE() InformationElement {
+	if p.Wide || p.Dest > 0xFF || p.Src > 0xFF {
+		return InformationElement{IEPorts16, []byte{byte(p.Dest >> 8), byte(p.Dest), byte(p.Src >> 8), byte(p.Src)}}
+	}
+	return InformationElement{IEPorts8, []byte{byte(p.Dest), byte(p.Src)}}
+}
+
+// Ports returns the application port addressing of IE 0x04 or 0x05
+func (h UserDataHeader) Ports() (PortAddress, bool) {
+	for _, ie := range h {
+		switch data := ie.Data; {
+		case ie.ID == IEPorts8 && len(data) == 2:
+			return PortAddress{Dest: uint16(data[0]), Src: uint16(data[1])}, true
+		case ie.ID == IEPorts16 && len(data) == 4:
+			return PortAddress{Dest: binary.BigEndian.Uint16(data), Src: binary.BigEndian.Uint16(data[2:]), Wide: true}, true
+		}
+	}
+	return PortAddress{}, false
+}
+
+// Indication types of SpecialSMSIndication
+const (
+	IndicationVoicemail byte = 0
+	IndicationFax       byte = 1
+	IndicationEmail     byte = 2
+	IndicationOther     byte = 3
+	IndicationVideo     byte = 7 // extended message type
+)
+
+// SpecialSMSIndication is the message waiting indication of IE 0x01, see 3GPP TS 23.040, section 9.2.3.24.2
+type SpecialSMSIndication struct {
+	// Store keeps the message once the indication is updated, otherwise it may be discarded
+	Store bool
+	// Type holds the indication type in bits 1-0, the extended type in bits 4-2 and the profile in bits 6-5
+	Type  byte
+	Count byte // waiting messages, 0 clears the indication
+}
+
+// IE ...
+func (s SpecialSMSIndication) IE() InformationElement {
+	return InformationElement{IESpecialSMS, []byte{getBool(s.Store)<<7 | s.Type&0x7F, s.Count}}
+}
+
+// SpecialSMSIndications returns every IE 0x01, one per indication type
+func (h UserDataHeader) SpecialSMSIndications() (found []SpecialSMSIndication) {
+	for _, ie := range h.FindAll(IESpecialSMS) {
+		if len(ie.Data) == 2 {
+			found = append(found, SpecialSMSIndication{Store: ie.Data[0]&0x80 != 0, Type: ie.Data[0] & 0x7F, Count: ie.Data[1]})
+		}
+	}
+	return
+}
+
+// Text alignments and font sizes of TextFormatting
+const (
+	AlignLeft    byte = 0
+	AlignCenter  byte = 1
+	AlignRight   byte = 2
+	AlignDefault byte = 3
+
+	FontNormal byte = 0
+	FontLarge  byte = 1
+	FontSmall  byte = 2
+)
+
+// TextFormatting is the EMS text formatting of IE 0x0A, see 3GPP TS 23.040, section 9.2.3.24.10.1.1
+type TextFormatting struct {
+	Start         byte // position of the first formatted character
+	Length        byte
+	Alignment     byte
+	FontSize      byte
+	Bold          bool
+	Italic        bool
+	Underline     bool
+	Strikethrough bool
+	// Color holds foreground in bits 3-0 and background in bits 7-4, nil leaves it out
+	Color *byte
+}
+
+// IE ...
+func (f TextFormatting) IE() InformationElement {
+	mode := f.Alignment&0b11 | f.FontSize&0b11<<2 |
+		getBool(f.Bold)<<4 | getBool(f.Italic)<<5 | getBool(f.Underline)<<6 | getBool(f.Strikethrough)<<7
+	data := []byte{f.Start, f.Length, mode}
+	if f.Color != nil {
+		data = append(data, *f.Color)
+	}
+	return InformationElement{IETextFormatting, data}
+}
+
+// TextFormattings returns every IE 0x0A in order
+func (h UserDataHeader) TextFormattings() (found []TextFormatting) {
+	for _, ie := range h.FindAll(IETextFormatting) {
+		if len(ie.Data) != 3 && len(ie.Data) != 4 {
+			continue
+		}
+		mode := ie.Data[2]
+		f := TextFormatting{
+			Start: ie.Data[0], Length: ie.Data[1],
+			Alignment: mode & 0b11, FontSize: mode >> 2 & 0b11,
+			Bold: mode&0x10 != 0, Italic: mode&0x20 != 0, Underline: mode&0x40 != 0, Strikethrough: mode&0x80 != 0,
+		}
+		if len(ie.Data) == 4 {
+			color := ie.Data[3]
+			f.Color = &color
+		}
+		found = append(found, f)
+	}
+	return
+}
+
+// SIMToolkitSecurity returns the empty (U)SIM toolkit security header IE, id is 0x70 to 0x7F
+func SIMToolkitSecurity(id byte) InformationElement {
+	return InformationElement{ID: id, Data: []byte{}}
+}
+
+// SIMToolkitSecurity returns the id of the (U)SIM toolkit security header, e.g. IECommandPacket
+func (h UserDataHeader) SIMToolkitSecurity() (byte, bool) {
+	for _, ie := range h {
+		if ie.ID >= IECommandPacket && ie.ID <= IESIMToolkitMax {
+			return ie.ID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,118 @@
+package pdu
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+var udhSeeds = [][]byte{
+	{},
+	{0},
+	{5, 0, 3, 0x2A, 2, 1, 'h', 'i'},
+	{6, 8, 4, 0, 0x2A, 2, 1},
+	{6, 5, 4, 0x0B, 0x84, 0x23, 0xF0},
+	{3, 0x99, 0, 0xAA},
+	{5, 0, 3, 0x2A, 2}, // UDHL past the data
+	{2, 0, 3, 0x2A},    // IE past UDHL
+	{0xFF},
+}
+
+// udhPastEnd reports whether the UDHL of data announces more octets than data holds
+func udhPastEnd(data []byte) bool {
+	return len(data) > 0 && 1+int(data[0]) > len(data)
+}
+
+func FuzzDecodeUserDataHeader(f *testing.F) {
+	for _, seed := range udhSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		h, n, err := DecodeUserDataHeader(data)
+		if err != nil {
+			var udhErr *UDHError
+			if !errors.As(err, &udhErr) || udhErr.CommandStatus() != ESME_RINVMSGLEN {
+				t.Fatalf("%x: got %v, want a UDHError", data, err)
+			}
+			return
+		}
+		if udhPastEnd(data) {
+			t.Fatalf("%x: UDHL past the data accepted", data)
+		}
+		if n != h.Len() || n > len(data) {
+			t.Fatalf("%x: decoded %d octets, UDH takes %d", data, n, h.Len())
+		}
+		encoded, err := h.appendTo(nil)
+		if err != nil {
+			t.Fatalf("%x: %v", data, err)
+		}
+		if !bytes.Equal(encoded, data[:n]) {
+			t.Fatalf("round trip %x, want %x", encoded, data[:n])
+		}
+	})
+}
+
+// udhiFrame returns the submit_sm frame carrying message as short_message with the UDHI set
+func udhiFrame(t *testing.T, message []byte) []byte {
+	p := testSubmitSM()
+	p.ShortMessage.Message = nil
+	withUDHI := *p
+	withUDHI.ESMClass.UDHIndicator = true
+	// without a message the frames only differ in esm_class
+	plain, udhi := encode(t, p), encode(t, &withUDHI)
+
+	p.ShortMessage.Message = message
+	frame := encode(t, p)
+	for i := range plain {
+		if plain[i] != udhi[i] {
+			frame[i] = udhi[i]
+			return frame
+		}
+	}
+	t.Fatalf("no esm_class in %x", plain)
+	return nil
+}
+
+func FuzzShortMessage(f *testing.F) {
+	for _, seed := range udhSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, message []byte) {
+		if len(message) > 0xFF {
+			return
+		}
+		frame := udhiFrame(t, message)
+		packet, _, perr := DecodePDU(frame)
+		if udhPastEnd(message) && (perr == nil || perr.CommandStatus != ESME_RINVMSGLEN) {
+			t.Fatalf("%x: got %v, want ESME_RINVMSGLEN", message, perr)
+		}
+		if perr != nil {
+			if perr.CommandStatus != ESME_RINVMSGLEN || packet == nil {
+				t.Fatalf("%x: %v", message, perr)
+			}
+			return
+		}
+
+		got := encode(t, packet)
+		if !bytes.Equal(got, frame) {
+			t.Fatalf("round trip %x, want %x", got, frame)
+		}
+		m := packet.(*SubmitSM).ShortMessage
+		if m.UDHeader.Len()+len(m.Message) != len(message) {
+			t.Fatalf("%x: UDH %d and message %d octets", message, m.UDHeader.Len(), len(m.Message))
+		}
+	})
+}
+
+func TestUserDataHeaderSetKeepsShared(t *testing.T) {
+	base := UserDataHeader{{ID: IEConcatenated8, Data: []byte{1, 2, 1}}, {ID: IEPorts16}, {ID: IEConcatenated8}}
+	h := base
+	h.Set(InformationElement{ID: IEConcatenated8, Data: []byte{9, 2, 2}})
+
+	if len(h) != 2 || h[0].Data[0] != 9 || h[1].ID != IEPorts16 {
+		t.Errorf("Set: got %+v", h)
+	}
+	if base[0].Data[0] != 1 || base[1].ID != IEPorts16 || base[2].ID != IEConcatenated8 {
+		t.Errorf("shared UDH changed to %+v", base)
+	}
+}
@@ -0,0 +1,146 @@
+package pdu
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Vendor specific TLVs see SMPP v5, section 4.8.1 (133p)
+const (
+	TagVendorMin uint16 = 0x1400
+	TagVendorMax uint16 = 0x3FFF
+)
+
+// VendorProfile groups the proprietary TLVs of one MC or carrier
+type VendorProfile struct {
+	Name string
+
+	mu   sync.RWMutex
+	tags map[uint16]TagDefinition
+}
+
+var (
+	profilesMu sync.RWMutex
+	profiles   = make(map[string]*VendorProfile)
+
+	defaultProfile atomic.Value
+)
+
+// SetDefaultVendorProfile selects the profile that names vendor TLVs in MarshalJSON, UnmarshalJSON
+// and String of Tags. It is process-wide, binds on different profiles use Tags.Format,
+// Tags.EncodeJSON and Tags.DecodeJSON with the profile of their connection instead.
+func SetDefaultVendorProfile(p *VendorProfile) {
+	defaultProfile.Store(p)
+}
+
+// DefaultVendorProfile returns the profile set by SetDefaultVendorProfile, nil means standard TLVs only
+func DefaultVendorProfile() *VendorProfile {
+	p, _ := defaultProfile.Load().(*VendorProfile)
+	return p
+}
+
+// NewVendorProfile ...
+func NewVendorProfile(name string) *VendorProfile {
+	return &VendorProfile{Name: name, tags: make(map[uint16]TagDefinition)}
+}
+
+// RegisterVendorProfile makes p available to LookupVendorProfile by its name
+func RegisterVendorProfile(p *VendorProfile) {
+	profilesMu.Lock()
+	profiles[p.Name] = p
+	profilesMu.Unlock()
+}
+
+// LookupVendorProfile returns the registered profile or nil
+func LookupVendorProfile(name string) *VendorProfile {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	return profiles[name]
+}
+
+// Register adds a vendor TLV, the tag must be in the 0x1400-0x3FFF range.
+// A zero MaxLength allows values up to the TLV limit, integer types get their fixed length
+// and a C-Octet string at least its NULL terminator.
+func (p *VendorProfile) Register(def TagDefinition) error {
+	if def.Tag < TagVendorMin || def.Tag > TagVendorMax || def.Name == "" {
+		return ErrInvalidVendorTag
+	}
+	switch def.Type {
+	case TagTypeUint8:
+		def.MinLength, def.MaxLength = 1, 1
+	case TagTypeUint16:
+		def.MinLength, def.MaxLength = 2, 2
+	case TagTypeUint32:
+		def.MinLength, def.MaxLength = 4, 4
+	case TagTypeCString:
+		if def.MinLength < 1 {
+			def.MinLength = 1 // the NULL terminator
+		}
+	}
+	if def.MaxLength == 0 {
+		def.MaxLength = 0xFFFE
+	}
+	p.mu.Lock()
+	p.tags[def.Tag] = def
+	p.mu.Unlock()
+	return nil
+}
+
+// MustRegister is like Register but panics on error
+func (p *VendorProfile) MustRegister(def TagDefinition) *VendorProfile {
+	if err := p.Register(def); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+// Lookup returns the definition of tag, standard TLVs first. A nil profile knows the standard ones only.
+func (p *VendorProfile) Lookup(tag uint16) (TagDefinition, bool) {
+	if def, ok := TagRegistry[tag]; ok {
+		return def, true
+	}
+	if p == nil {
+		return TagDefinition{}, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	def, ok := p.tags[tag]
+	return def, ok
+}
+
+// LookupName returns the definition by its name
+func (p *VendorProfile) LookupName(name string) (TagDefinition, bool) {
+	for _, def := range TagRegistry {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	if p == nil {
+		return TagDefinition{}, false
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for _, def := range p.tags {
+		if def.Name == name {
+			return def, true
+		}
+	}
+	return TagDefinition{}, false
+}
+
+// Validate checks the vendor TLVs of t known to the profile
+func (p *VendorProfile) Validate(t Tags) error {
+	if p == nil {
+		return nil
+	}
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	for tag, data := range t {
+		if def, ok := p.tags[tag]; ok {
+			if err := def.Validate(data); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
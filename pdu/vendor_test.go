@@ -0,0 +1,73 @@
+package pdu
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVendorProfileValidateEmptyValues(t *testing.T) {
+	profile := NewVendorProfile("test").
+		MustRegister(TagDefinition{Tag: 0x1400, Name: "vendor_string", Type: TagTypeCString}).
+		MustRegister(TagDefinition{Tag: 0x1401, Name: "vendor_byte", Type: TagTypeUint8})
+
+	for _, tag := range []uint16{0x1400, 0x1401, TagReceiptedMessageID} {
+		tags := Tags{tag: {}}
+		if err := profile.Validate(tags); tag != TagReceiptedMessageID && err == nil {
+			t.Errorf("0x%04X: empty value accepted", tag)
+		}
+		if def, ok := profile.Lookup(tag); ok && def.Validate(nil) == nil {
+			t.Errorf("0x%04X: empty value accepted", tag)
+		}
+		_ = tags.Format(profile)
+	}
+
+	if err := profile.Validate(Tags{0x1400: []byte("abc\x00")}); err != nil {
+		t.Errorf("valid string rejected: %v", err)
+	}
+	if err := profile.Validate(Tags{0x1400: []byte("abc")}); !errors.Is(err, ESME_RINVOPTPARAMVAL) {
+		t.Errorf("unterminated string: got %v", err)
+	}
+}
+
+func TestTagsJSONPerProfile(t *testing.T) {
+	a := NewVendorProfile("a").MustRegister(TagDefinition{Tag: 0x1400, Name: "a_id", Type: TagTypeUint8})
+	b := NewVendorProfile("b").MustRegister(TagDefinition{Tag: 0x1400, Name: "b_id", Type: TagTypeUint8})
+	tags := Tags{0x1400: {7}}
+
+	for _, tt := range []struct {
+		profile *VendorProfile
+		want    string
+	}{
+		{a, `{"a_id":7}`},
+		{b, `{"b_id":7}`},
+		{nil, `{"0x1400":"07"}`},
+	} {
+		data, err := tags.EncodeJSON(tt.profile)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("%v: got %s, %v, want %s", tt.profile, data, err, tt.want)
+			continue
+		}
+		var decoded Tags
+		if err = decoded.DecodeJSON(data, tt.profile); err != nil || len(decoded[0x1400]) != 1 || decoded[0x1400][0] != 7 {
+			t.Errorf("%s: decoded %v, %v", data, decoded, err)
+		}
+	}
+
+	// the default profile may change while other goroutines marshal
+	defer SetDefaultVendorProfile(DefaultVendorProfile())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetDefaultVendorProfile(a)
+			SetDefaultVendorProfile(b)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		if _, err := tags.MarshalJSON(); err != nil {
+			t.Fatal(err)
+		}
+		_ = tags.String()
+	}
+	<-done
+}
@@ -0,0 +1,550 @@
+package pdu
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// WAP Push over SMS, see WAP-259-WDP (ports), WAP-230-WSP (push PDU), WAP-192-WBXML,
+// WAP-167-ServiceInd and WAP-168-ServiceLoad
+const (
+	WAPPushPort       uint16 = 2948 // WAP Push connectionless session service (client side)
+	WAPPushSourcePort uint16 = 9200 // WAP connectionless session service
+
+	WSPPush byte = 0x06
+
+	// Well-known content types with the short-integer bit set
+	ContentTypeSI           byte = 0xAE // application/vnd.wap.sic
+	ContentTypeSL           byte = 0xB0 // application/vnd.wap.slc
+	ContentTypeConnectivity byte = 0xB6 // application/vnd.wap.connectivity-wbxml, OTA provisioning
+)
+
+// WAPPush is a WSP push PDU
+type WAPPush struct {
+	TransactionID byte
+	// ContentType is a well-known content type, e.g. ContentTypeSI. Zero means Headers start
+	// with a textual content type.
+	ContentType byte
+	Headers     []byte // encoded WSP headers after the content type
+	Body        []byte
+}
+
+// MarshalBinary ...
+func (p WAPPush) MarshalBinary() ([]byte, error) {
+	headers := make([]byte, 0, 1+len(p.Headers))
+	if p.ContentType != 0 {
+		headers = append(headers, p.ContentType)
+	}
+	headers = append(headers, p.Headers...)
+
+	data := make([]byte, 0, 8+len(headers)+len(p.Body))
+	data = append(data, p.TransactionID, WSPPush)
+	data = appendUintvar(data, uint32(len(headers)))
+	data = append(data, headers...)
+	return append(data, p.Body...), nil
+}
+
+// ParseWAPPush decodes a WSP push PDU
+func ParseWAPPush(data []byte) (*WAPPush, error) {
+	if len(data) < 3 || data[1] != WSPPush {
+		return nil, ErrInvalidWAPPush
+	}
+	length, n, ok := readUintvar(data[2:])
+	if !ok || uint32(len(data)-2-n) < length {
+		return nil, ErrInvalidWAPPush
+	}
+	headers := data[2+n : 2+n+int(length)]
+	p := &WAPPush{TransactionID: data[0], Body: data[2+n+int(length):]}
+	if len(headers) > 0 && headers[0] >= 0x80 {
+		p.ContentType, headers = headers[0], headers[1:]
+	}
+	p.Headers = headers
+	return p, nil
+}
+
+// NewWAPPushSubmitSM encodes the push and splits it into copies of template addressed to
+// WAPPushPort, see SplitBinarySubmitSM
+func NewWAPPushSubmitSM(template *SubmitSM, push WAPPush, opts SplitOptions) ([]*SubmitSM, error) {
+	data, err := push.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return newPortSubmitSM(template, WAPPushPort, WAPPushSourcePort, data, opts)
+}
+
+// NewServiceIndicationSubmitSM builds the submit_sm parts of a WAP Push Service Indication
+func NewServiceIndicationSubmitSM(template *SubmitSM, si ServiceIndication, opts SplitOptions) ([]*SubmitSM, error) {
+	return NewWAPPushSubmitSM(template, WAPPush{TransactionID: 1, ContentType: ContentTypeSI, Body: si.MarshalWBXML()}, opts)
+}
+
+// NewServiceLoadingSubmitSM builds the submit_sm parts of a WAP Push Service Loading
+func NewServiceLoadingSubmitSM(template *SubmitSM, sl ServiceLoading, opts SplitOptions) ([]*SubmitSM, error) {
+	return NewWAPPushSubmitSM(template, WAPPush{TransactionID: 1, ContentType: ContentTypeSL, Body: sl.MarshalWBXML()}, opts)
+}
+
+// DecodeWAPPush joins the parts of a WAP Push addressed to WAPPushPort, in any order, and decodes it
+func DecodeWAPPush(parts ...*ShortMessage) (*WAPPush, error) {
+	data, err := joinBinary(WAPPushPort, parts)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWAPPush(data)
+}
+
+// joinBinary concatenates the parts addressed to port in the order of their concatenation IE.
+// More than one part needs sequences 1 to N exactly once under a single reference.
+func joinBinary(port uint16, parts []*ShortMessage) ([]byte, error) {
+	if len(parts) == 0 {
+		return nil, ErrIncompleteMessage
+	}
+	ordered := make([]*ShortMessage, len(parts))
+	var reference uint16
+	for i, m := range parts {
+		if ports, ok := m.UDHeader.Ports(); !ok || ports.Dest != port {
+			return nil, ErrInvalidPortAddress
+		}
+		h := m.UDHeader.ConcatenatedHeader()
+		if h == nil {
+			if len(parts) > 1 {
+				return nil, ErrIncompleteMessage
+			}
+			ordered[0] = m
+			continue
+		}
+		if i == 0 {
+			reference = h.Reference
+		}
+		seq := int(h.Sequence)
+		if int(h.TotalParts) != len(parts) || h.Reference != reference || seq < 1 || seq > len(parts) || ordered[seq-1] != nil {
+			return nil, ErrIncompleteMessage
+		}
+		ordered[seq-1] = m
+	}
+	var data []byte
+	for _, m := range ordered {
+		data = append(data, m.Message...)
+	}
+	return data, nil
+}
+
+// appendUintvar see WAP-230-WSP, section 8.1.2
+func appendUintvar(buf []byte, v uint32) []byte {
+	var tmp [5]byte
+	i := len(tmp) - 1
+	tmp[i] = byte(v & 0x7F)
+	for v >>= 7; v > 0; v >>= 7 {
+		i--
+		tmp[i] = byte(v&0x7F) | 0x80
+	}
+	return append(buf, tmp[i:]...)
+}
+
+func readUintvar(data []byte) (v uint32, n int, ok bool) {
+	for n < len(data) && n < 5 {
+		c := data[n]
+		n++
+		v = v<<7 | uint32(c&0x7F)
+		if c&0x80 == 0 {
+			return v, n, true
+		}
+	}
+	return 0, 0, false
+}
+
+// WBXML global tokens, see WAP-192-WBXML, section 5.8.4
+const (
+	wbxmlVersion  byte = 0x02 // 1.2
+	wbxmlUTF8     byte = 0x6A // MIBenum of UTF-8
+	wbxmlEnd      byte = 0x01
+	wbxmlStrI     byte = 0x03
+	wbxmlOpaque   byte = 0xC3
+	wbxmlAttrs    byte = 0x80
+	wbxmlContent  byte = 0x40
+	wbxmlPublicSI byte = 0x05 // -//WAPFORUM//DTD SI 1.0//EN
+	wbxmlPublicSL byte = 0x06 // -//WAPFORUM//DTD SL 1.0//EN
+)
+
+// wbxmlValues are the attribute value tokens SI and SL share
+var wbxmlValues = map[byte]string{0x85: ".com/", 0x86: ".edu/", 0x87: ".net/", 0x88: ".org/"}
+
+// wbxmlAttribute is an attribute start token, prefix is the start of the value it implies
+type wbxmlAttribute struct {
+	token  byte
+	name   string
+	prefix string
+}
+
+// SIAction see WAP-167-ServiceInd, section 5.2.1
+type SIAction byte
+
+const (
+	SIActionDefault      SIAction = 0 // no attribute, signal-medium
+	SIActionSignalNone   SIAction = 0x05
+	SIActionSignalLow    SIAction = 0x06
+	SIActionSignalMedium SIAction = 0x07
+	SIActionSignalHigh   SIAction = 0x08
+	SIActionDelete       SIAction = 0x09
+)
+
+var siAttributes = []wbxmlAttribute{
+	{0x05, "action", "signal-none"},
+	{0x06, "action", "signal-low"},
+	{0x07, "action", "signal-medium"},
+	{0x08, "action", "signal-high"},
+	{0x09, "action", "delete"},
+	{0x0A, "created", ""},
+	{0x0B, "href", ""},
+	{0x0C, "href", "http://"},
+	{0x0D, "href", "http://www."},
+	{0x0E, "href", "https://"},
+	{0x0F, "href", "https://www."},
+	{0x10, "si-expires", ""},
+	{0x11, "si-id", ""},
+}
+
+// ServiceIndication is a WAP Push SI, zero dates and an empty ID are left out
+type ServiceIndication struct {
+	Href    string
+	ID      string
+	Created time.Time
+	Expires time.Time
+	Action  SIAction
+	Text    string
+}
+
+// MarshalWBXML encodes the SI as application/vnd.wap.sic
+func (si ServiceIndication) MarshalWBXML() []byte {
+	const tagSI, tagIndication byte = 0x05, 0x06
+
+	buf := []byte{wbxmlVersion, wbxmlPublicSI, wbxmlUTF8, 0, tagSI | wbxmlContent}
+	indication := tagIndication | wbxmlAttrs
+	if si.Text != "" {
+		indication |= wbxmlContent
+	}
+	buf = append(buf, indication)
+	buf = appendWBXMLAttribute(buf, siAttributes, "href", si.Href)
+	if si.ID != "" {
+		buf = appendWBXMLAttribute(buf, siAttributes, "si-id", si.ID)
+	}
+	if !si.Created.IsZero() {
+		buf = append(buf, 0x0A)
+		buf = appendWBXMLDate(buf, si.Created)
+	}
+	if !si.Expires.IsZero() {
+		buf = append(buf, 0x10)
+		buf = appendWBXMLDate(buf, si.Expires)
+	}
+	if si.Action != SIActionDefault {
+		buf = append(buf, byte(si.Action))
+	}
+	buf = append(buf, wbxmlEnd)
+	if si.Text != "" {
+		buf = appendWBXMLString(buf, si.Text)
+		buf = append(buf, wbxmlEnd)
+	}
+	return append(buf, wbxmlEnd)
+}
+
+// ParseServiceIndication decodes application/vnd.wap.sic
+func ParseServiceIndication(data []byte) (*ServiceIndication, error) {
+	const tagSI, tagIndication byte = 0x05, 0x06
+
+	r, err := newWBXMLReader(data, wbxmlPublicSI)
+	if err != nil {
+		return nil, err
+	}
+	if tag := r.next(); tag&0x3F != tagSI {
+		return nil, ErrInvalidWBXML
+	}
+	tag := r.next()
+	if tag&0x3F != tagIndication {
+		return nil, ErrInvalidWBXML
+	}
+	si := &ServiceIndication{}
+	if tag&wbxmlAttrs != 0 {
+		err = r.attributes(siAttributes, func(token byte, name string, value []byte) (err error) {
+			switch name {
+			case "href":
+				si.Href = string(value)
+			case "si-id":
+				si.ID = string(value)
+			case "created":
+				si.Created, err = parseWBXMLDate(value)
+			case "si-expires":
+				si.Expires, err = parseWBXMLDate(value)
+			case "action":
+				si.Action = SIAction(token)
+			}
+			return
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if tag&wbxmlContent != 0 {
+		if si.Text, err = r.content(); err != nil {
+			return nil, err
+		}
+	}
+	return si, r.err
+}
+
+// SLAction see WAP-168-ServiceLoad, section 5.2.1
+type SLAction byte
+
+const (
+	SLActionDefault     SLAction = 0 // no attribute, execute-low
+	SLActionExecuteLow  SLAction = 0x05
+	SLActionExecuteHigh SLAction = 0x06
+	SLActionCache       SLAction = 0x07
+)
+
+var slAttributes = []wbxmlAttribute{
+	{0x05, "action", "execute-low"},
+	{0x06, "action", "execute-high"},
+	{0x07, "action", "cache"},
+	{0x08, "href", ""},
+	{0x09, "href", "http://"},
+	{0x0A, "href", "http://www."},
+	{0x0B, "href", "https://"},
+	{0x0C, "href", "https://www."},
+}
+
+// ServiceLoading is a WAP Push SL
+type ServiceLoading struct {
+	Href   string
+	Action SLAction
+}
+
+// MarshalWBXML encodes the SL as application/vnd.wap.slc
+func (sl ServiceLoading) MarshalWBXML() []byte {
+	const tagSL byte = 0x05
+
+	buf := []byte{wbxmlVersion, wbxmlPublicSL, wbxmlUTF8, 0, tagSL | wbxmlAttrs}
+	buf = appendWBXMLAttribute(buf, slAttributes, "href", sl.Href)
+	if sl.Action != SLActionDefault {
+		buf = append(buf, byte(sl.Action))
+	}
+	return append(buf, wbxmlEnd)
+}
+
+// ParseServiceLoading decodes application/vnd.wap.slc
+func ParseServiceLoading(data []byte) (*ServiceLoading, error) {
+	const tagSL byte = 0x05
+
+	r, err := newWBXMLReader(data, wbxmlPublicSL)
+	if err != nil {
+		return nil, err
+	}
+	tag := r.next()
+	if tag&0x3F != tagSL {
+		return nil, ErrInvalidWBXML
+	}
+	sl := &ServiceLoading{}
+	if tag&wbxmlAttrs != 0 {
+		err = r.attributes(slAttributes, func(token byte, name string, value []byte) error {
+			switch name {
+			case "href":
+				sl.Href = string(value)
+			case "action":
+				sl.Action = SLAction(token)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return sl, r.err
+}
+
+// appendWBXMLAttribute writes the attribute start with the longest matching prefix and
+// the rest of the value, using value tokens where they fit
+func appendWBXMLAttribute(buf []byte, attributes []wbxmlAttribute, name, value string) []byte {
+	var start *wbxmlAttribute
+	for i := range attributes {
+		a := &attributes[i]
+		if a.name == name && strings.HasPrefix(value, a.prefix) && (start == nil || len(a.prefix) > len(start.prefix)) {
+			start = a
+		}
+	}
+	if start == nil {
+		return buf
+	}
+	buf = append(buf, start.token)
+	value = value[len(start.prefix):]
+	for value != "" {
+		at, token := len(value), byte(0)
+		for t, s := range wbxmlValues {
+			if i := strings.Index(value, s); i >= 0 && i < at {
+				at, token = i, t
+			}
+		}
+		if at > 0 {
+			buf = appendWBXMLString(buf, value[:at])
+		}
+		if token == 0 {
+			break
+		}
+		buf = append(buf, token)
+		value = value[at+len(wbxmlValues[token]):]
+	}
+	return buf
+}
+
+func appendWBXMLString(buf []byte, s string) []byte {
+	buf = append(buf, wbxmlStrI)
+	buf = append(buf, s...)
+	return append(buf, 0)
+}
+
+// appendWBXMLDate writes the date as OPAQUE BCD digits without trailing zero octets,
+// see WAP-167-ServiceInd, section 8.2.2
+func appendWBXMLDate(buf []byte, t time.Time) []byte {
+	digits := t.UTC().Format("20060102150405")
+	date := make([]byte, 0, 7)
+	for i := 0; i < len(digits); i += 2 {
+		date = append(date, (digits[i]-'0')<<4|(digits[i+1]-'0'))
+	}
+	for len(date) > 0 && date[len(date)-1] == 0 {
+		date = date[:len(date)-1]
+	}
+	buf = append(buf, wbxmlOpaque, byte(len(date)))
+	return append(buf, date...)
+}
+
+func parseWBXMLDate(data []byte) (time.Time, error) {
+	if len(data) > 7 {
+		return time.Time{}, ErrInvalidWBXML
+	}
+	var digits [14]byte
+	for i := range digits {
+		digits[i] = '0'
+	}
+	for i, c := range data {
+		if c>>4 > 9 || c&0x0F > 9 {
+			return time.Time{}, ErrInvalidWBXML
+		}
+		digits[2*i], digits[2*i+1] = '0'+c>>4, '0'+c&0x0F
+	}
+	t, err := time.Parse("20060102150405", string(digits[:]))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidWBXML, err.Error())
+	}
+	return t, nil
+}
+
+// wbxmlReader reads the tokens of an inline-string only WBXML document
+type wbxmlReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func newWBXMLReader(data []byte, publicID byte) (*wbxmlReader, error) {
+	// version, public identifier, charset and an empty string table
+	if len(data) < 4 || data[1] != publicID || data[2] != wbxmlUTF8 || data[3] != 0 {
+		return nil, ErrInvalidWBXML
+	}
+	return &wbxmlReader{data: data, pos: 4}, nil
+}
+
+func (r *wbxmlReader) next() byte {
+	if r.pos >= len(r.data) {
+		r.err = ErrInvalidWBXML
+		return wbxmlEnd
+	}
+	c := r.data[r.pos]
+	r.pos++
+	return c
+}
+
+func (r *wbxmlReader) peek() byte {
+	if r.pos >= len(r.data) {
+		return wbxmlEnd
+	}
+	return r.data[r.pos]
+}
+
+func (r *wbxmlReader) string() string {
+	end := bytes.IndexByte(r.data[r.pos:], 0)
+	if end < 0 {
+		r.err = ErrInvalidWBXML
+		return ""
+	}
+	s := string(r.data[r.pos : r.pos+end])
+	r.pos += end + 1
+	return s
+}
+
+func (r *wbxmlReader) opaque() []byte {
+	n := int(r.next())
+	if r.pos+n > len(r.data) {
+		r.err = ErrInvalidWBXML
+		return nil
+	}
+	data := r.data[r.pos : r.pos+n]
+	r.pos += n
+	return data
+}
+
+// attributes reads attributes up to END and passes each one to fn
+func (r *wbxmlReader) attributes(known []wbxmlAttribute, fn func(token byte, name string, value []byte) error) error {
+	for r.err == nil {
+		token := r.next()
+		if token == wbxmlEnd {
+			return r.err
+		}
+		var start *wbxmlAttribute
+		for i := range known {
+			if known[i].token == token {
+				start = &known[i]
+			}
+		}
+		if start == nil {
+			return ErrInvalidWBXML
+		}
+		value := []byte(start.prefix)
+		if start.name == "action" {
+			value = nil
+		}
+	values:
+		for r.err == nil {
+			switch c := r.peek(); {
+			case c == wbxmlStrI:
+				r.pos++
+				value = append(value, r.string()...)
+			case c == wbxmlOpaque:
+				r.pos++
+				value = append(value, r.opaque()...)
+			case wbxmlValues[c] != "":
+				r.pos++
+				value = append(value, wbxmlValues[c]...)
+			default:
+				break values
+			}
+		}
+		if err := fn(token, start.name, value); err != nil {
+			return err
+		}
+	}
+	return r.err
+}
+
+// content reads the inline strings of an element up to its END
+func (r *wbxmlReader) content() (string, error) {
+	var text strings.Builder
+	for r.err == nil {
+		switch r.next() {
+		case wbxmlEnd:
+			return text.String(), r.err
+		case wbxmlStrI:
+			text.WriteString(r.string())
+		default:
+			return "", ErrInvalidWBXML
+		}
+	}
+	return "", r.err
+}
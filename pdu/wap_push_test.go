@@ -0,0 +1,123 @@
+package pdu
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func shortMessages(parts []*SubmitSM) []*ShortMessage {
+	messages := make([]*ShortMessage, len(parts))
+	for i, p := range parts {
+		messages[i] = &p.ShortMessage
+	}
+	return messages
+}
+
+func TestDecodeWAPPushParts(t *testing.T) {
+	push := WAPPush{TransactionID: 1, ContentType: ContentTypeSL, Body: ServiceLoading{Href: "http://" + strings.Repeat("x", 300)}.MarshalWBXML()}
+	parts, err := NewWAPPushSubmitSM(testSubmitSM(), push, SplitOptions{Reference: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 3 {
+		t.Fatalf("%d parts, want 3", len(parts))
+	}
+	other, err := NewWAPPushSubmitSM(testSubmitSM(), push, SplitOptions{Reference: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	single, err := NewWAPPushSubmitSM(testSubmitSM(), WAPPush{TransactionID: 1, ContentType: ContentTypeSL}, SplitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := shortMessages(parts)
+	want, _ := push.MarshalBinary()
+
+	got, err := DecodeWAPPush(m[2], m[0], m[1])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := got.MarshalBinary(); !bytes.Equal(data, want) {
+		t.Errorf("got %x, want %x", data, want)
+	}
+	if _, err = DecodeWAPPush(shortMessages(single)...); err != nil {
+		t.Errorf("single part: %v", err)
+	}
+
+	for name, parts := range map[string][]*ShortMessage{
+		"lone part":       {m[0]},
+		"missing part":    {m[0], m[1]},
+		"duplicate part":  {m[0], m[1], m[1]},
+		"mixed reference": {m[0], &other[1].ShortMessage, m[2]},
+		"no concat IE":    {m[0], m[1], &single[0].ShortMessage},
+	} {
+		if _, err := DecodeWAPPush(parts...); !errors.Is(err, ErrIncompleteMessage) {
+			t.Errorf("%s: got %v, want ErrIncompleteMessage", name, err)
+		}
+	}
+}
+
+func TestParseVCardDeliverSMLonePart(t *testing.T) {
+	parts, err := NewVCardSubmitSM(testSubmitSM(), VCard{Name: "Doe;John", Note: strings.Repeat("n", 200)}, SplitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) < 2 {
+		t.Fatalf("%d parts, want more than 1", len(parts))
+	}
+	if _, err = ParseVCardDeliverSM(&DeliverSM{Message: parts[0].ShortMessage}); !errors.Is(err, ErrIncompleteMessage) {
+		t.Errorf("got %v, want ErrIncompleteMessage", err)
+	}
+}
+
+func TestParseSmartMessagingInvalidBody(t *testing.T) {
+	card, err := NewVCardSubmitSM(testSubmitSM(), VCard{Name: "Doe;John"}, SplitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := NewVCalendarSubmitSM(testSubmitSM(), VCalendar{Summary: "meeting"}, SplitOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	card[0].ShortMessage.Message = []byte("no vcard")
+	entry[0].ShortMessage.Message = []byte("no vcalendar")
+
+	if got, err := ParseVCardDeliverSM(&DeliverSM{Message: card[0].ShortMessage}); err == nil || got != nil {
+		t.Errorf("vCard: got %+v, %v, want nil and an error", got, err)
+	}
+	if got, err := ParseVCalendarDeliverSM(&DeliverSM{Message: entry[0].ShortMessage}); err == nil || got != nil {
+		t.Errorf("vCalendar: got %+v, %v, want nil and an error", got, err)
+	}
+}
+
+func TestParseServiceIndicationDates(t *testing.T) {
+	si := ServiceIndication{
+		Href:    "http://example.com/",
+		ID:      "si1",
+		Created: time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC),
+		Expires: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC),
+		Action:  SIActionSignalHigh,
+		Text:    "hello",
+	}
+	data := si.MarshalWBXML()
+	got, err := ParseServiceIndication(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != si {
+		t.Errorf("got %+v, want %+v", *got, si)
+	}
+
+	// a created date that is no BCD fails the SI
+	i := bytes.Index(data, []byte{0x0A, wbxmlOpaque})
+	if i < 0 {
+		t.Fatal("created not found")
+	}
+	data[i+3] = 0xAA
+	if got, err = ParseServiceIndication(data); !errors.Is(err, ErrInvalidWBXML) || got != nil {
+		t.Errorf("got %+v, %v, want ErrInvalidWBXML", got, err)
+	}
+}
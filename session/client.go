@@ -0,0 +1,162 @@
+package session
+
+import (
+	"context"
+	"net"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+// BindType ...
+type BindType byte
+
+const (
+	Transmitter BindType = iota + 1
+	Receiver
+	Transceiver
+)
+
+// String ...
+func (t BindType) String() string {
+	switch t {
+	case Transmitter:
+		return "transmitter"
+	case Receiver:
+		return "receiver"
+	case Transceiver:
+		return "transceiver"
+	}
+	return "unknown"
+}
+
+func (t BindType) state() BindState {
+	switch t {
+	case Transmitter:
+		return StateBoundTX
+	case Receiver:
+		return StateBoundRX
+	case Transceiver:
+		return StateBoundTRX
+	}
+	return StateOpen
+}
+
+// Config describes how the ESME binds to the MC
+type Config struct {
+	Addr       string
+	BindType   BindType
+	SystemID   string
+	Password   string
+	SystemType string
+	Version    pdu.InterfaceVersion
+	TON        byte // see SMPP v5, section 4.7.1 (113p)
+	NPI        byte // see SMPP v5, section 4.7.2 (113p)
+	AddrRange  string
+
+	Settings
+
+	// Profile names and validates the vendor TLVs of this bind, optional
+	Profile *pdu.VendorProfile
+
+	// Handler receives deliver_sm, data_sm and other requests sent by the MC.
+	// It runs on the read loop and must not wait for responses of its own requests.
+	Handler HandlerFunc
+}
+
+// Client is a bound ESME session
+type Client struct {
+	*Conn
+	conf Config
+
+	// SystemID is the MC identifier returned in the bind response
+	SystemID string
+}
+
+// Dial connects to the MC and binds with conf.BindType
+func Dial(ctx context.Context, conf Config) (*Client, error) {
+	var d net.Dialer
+	nc, err := d.DialContext(ctx, "tcp", conf.Addr)
+	if err != nil {
+		return nil, err
+	}
+	return Bind(ctx, nc, conf)
+}
+
+// Bind binds over an already established connection
+func Bind(ctx context.Context, nc net.Conn, conf Config) (*Client, error) {
+	if conf.Version == 0 {
+		conf.Version = pdu.SMPPVersion34
+	}
+	if conf.BindType == 0 {
+		conf.BindType = Transceiver
+	}
+
+	c := &Client{conf: conf}
+	c.Conn = newConn(nc, conf.Settings, c.handle)
+	c.setProfile(conf.Profile)
+	go c.serve()
+
+	resp, err := c.Send(ctx, c.bindPDU())
+	if err != nil {
+		_ = c.closeWithError(err)
+		return nil, err
+	}
+
+	switch r := resp.(type) {
+	case *pdu.BindTransmitterResp:
+		c.SystemID = r.SystemID
+	case *pdu.BindReceiverResp:
+		c.SystemID = r.SystemID
+	case *pdu.BindTransceiverResp:
+		c.SystemID = r.SystemID
+	default:
+		_ = c.closeWithError(ErrUnexpectedResponse)
+		return nil, ErrUnexpectedResponse
+	}
+	c.setBindState(conf.BindType.state())
+	return c, nil
+}
+
+// BindType ...
+func (c *Client) BindType() BindType {
+	return c.conf.BindType
+}
+
+// Submit sends submit_sm and waits for submit_sm_resp
+func (c *Client) Submit(ctx context.Context, p *pdu.SubmitSM) (*pdu.SubmitSMResp, error) {
+	resp, err := c.Send(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	r, ok := resp.(*pdu.SubmitSMResp)
+	if !ok {
+		return nil, ErrUnexpectedResponse
+	}
+	return r, nil
+}
+
+func (c *Client) bindPDU() interface{} {
+	conf := c.conf
+	switch conf.BindType {
+	case Transmitter:
+		return &pdu.BindTransmitter{
+			SystemID: conf.SystemID, Password: conf.Password, SystemType: conf.SystemType,
+			Version: conf.Version, TON: conf.TON, NPI: conf.NPI, AddrRange: conf.AddrRange,
+		}
+	case Receiver:
+		return &pdu.BindReceiver{
+			SystemID: conf.SystemID, Password: conf.Password, SystemType: conf.SystemType,
+			Version: conf.Version, TON: conf.TON, NPI: conf.NPI, AddrRange: conf.AddrRange,
+		}
+	default:
+		return &pdu.BindTransceiver{
+			SystemID: conf.SystemID, Password: conf.Password, SystemType: conf.SystemType,
+			Version: conf.Version, TON: conf.TON, NPI: conf.NPI, AddrRange: conf.AddrRange,
+		}
+	}
+}
+
+// handle answers requests initiated by the MC
+func (c *Client) handle(conn *Conn, p interface{}, header *pdu.Header) {
+	conn.respond(p, c.conf.Handler)
+}
@@ -0,0 +1,155 @@
+package session
+
+import (
+	"context"
+	"net"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+// peer is the far end of a net.Pipe that a test drives PDU by PDU
+type peer struct {
+	t    *testing.T
+	conn net.Conn
+}
+
+// read returns the next PDU the connection under test wrote
+func (p *peer) read() interface{} {
+	p.t.Helper()
+	_ = p.conn.SetReadDeadline(time.Now().Add(time.Second))
+	packet, _, _, perr := pdu.ReadPDU(p.conn)
+	if perr != nil {
+		p.t.Fatalf("peer read: %v", perr)
+	}
+	return packet
+}
+
+func (p *peer) write(packet interface{}) error {
+	_ = p.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	if perr := pdu.WritePDU(p.conn, packet); perr != nil {
+		return perr
+	}
+	return nil
+}
+
+// answer writes the default response of req with its sequence and the status
+func (p *peer) answer(req interface{}, status pdu.CommandStatus) interface{} {
+	p.t.Helper()
+	resp := req.(pdu.Responsable).Resp()
+	pdu.WriteSequence(resp, pdu.ReadSequence(req))
+	pdu.WriteCommandStatus(resp, status)
+	if err := p.write(resp); err != nil {
+		p.t.Fatalf("peer write: %v", err)
+	}
+	return resp
+}
+
+// bindClient binds a client over a pipe whose other end is returned as a peer
+func bindClient(t *testing.T, conf Config) (*Client, *peer) {
+	t.Helper()
+	local, remote := net.Pipe()
+	p := &peer{t: t, conn: remote}
+	t.Cleanup(func() {
+		_ = local.Close()
+		_ = remote.Close()
+	})
+
+	errs := make(chan error, 1)
+	go func() {
+		_ = remote.SetReadDeadline(time.Now().Add(time.Second))
+		req, _, _, perr := pdu.ReadPDU(remote)
+		if perr != nil {
+			errs <- perr
+			return
+		}
+		resp := req.(pdu.Responsable).Resp()
+		pdu.WriteSequence(resp, pdu.ReadSequence(req))
+		errs <- p.write(resp)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	c, err := Bind(ctx, local, conf)
+	if err != nil {
+		t.Fatalf("Bind: %v", err)
+	}
+	if err = <-errs; err != nil {
+		t.Fatalf("peer bind: %v", err)
+	}
+	return c, p
+}
+
+func submitSM(text string) *pdu.SubmitSM {
+	p := &pdu.SubmitSM{}
+	p.ShortMessage.Message = []byte(text)
+	return p
+}
+
+func TestClientCorrelatesResponses(t *testing.T) {
+	c, p := bindClient(t, Config{})
+	if c.BindState() != StateBoundTRX {
+		t.Fatalf("bind state %s", c.BindState())
+	}
+
+	texts := []string{"a", "b", "c"}
+	type result struct {
+		text string
+		id   string
+		err  error
+	}
+	results := make(chan result, len(texts))
+	for _, text := range texts {
+		go func(text string) {
+			resp, err := c.Submit(context.Background(), submitSM(text))
+			if err != nil {
+				results <- result{text: text, err: err}
+				return
+			}
+			results <- result{text: text, id: resp.MessageID}
+		}(text)
+	}
+
+	var reqs []*pdu.SubmitSM
+	for range texts {
+		reqs = append(reqs, p.read().(*pdu.SubmitSM))
+	}
+	sort.Slice(reqs, func(i, j int) bool { return reqs[i].Header.Sequence < reqs[j].Header.Sequence })
+	if reqs[0].Header.Sequence == reqs[1].Header.Sequence || reqs[1].Header.Sequence == reqs[2].Header.Sequence {
+		t.Fatalf("sequences repeat: %d %d %d", reqs[0].Header.Sequence, reqs[1].Header.Sequence, reqs[2].Header.Sequence)
+	}
+	// the responses come back in reverse order, each names the message it answers
+	for i := len(reqs) - 1; i >= 0; i-- {
+		resp := &pdu.SubmitSMResp{Header: pdu.Header{Sequence: reqs[i].Header.Sequence}, MessageID: string(reqs[i].ShortMessage.Message)}
+		if err := p.write(resp); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for range texts {
+		r := <-results
+		if r.err != nil || r.id != r.text {
+			t.Errorf("%s: got %q, %v", r.text, r.id, r.err)
+		}
+	}
+	if n := c.InFlight(); n != 0 {
+		t.Errorf("%d requests in flight", n)
+	}
+}
+
+func TestClientStatusError(t *testing.T) {
+	c, p := bindClient(t, Config{})
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.Submit(context.Background(), submitSM("a"))
+		errs <- err
+	}()
+	p.answer(p.read(), pdu.ESME_RTHROTTLED)
+	err := <-errs
+	se, ok := err.(*StatusError)
+	if !ok || se.CommandStatus != pdu.ESME_RTHROTTLED || se.CommandID != pdu.SubmitSMID {
+		t.Errorf("got %v, want a throttled StatusError", err)
+	}
+}
@@ -0,0 +1,360 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrClosed             = errors.New("ConnectionClosed")
+	ErrUnexpectedResponse = errors.New("UnexpectedResponse")
+)
+
+// respBit marks response command IDs, see SMPP v5, section 4.7.5 (115p)
+const respBit pdu.CommandID = 0x80000000
+
+// StatusError is returned when the peer answers a request with a non-zero command_status
+type StatusError struct {
+	CommandID     pdu.CommandID
+	CommandStatus pdu.CommandStatus
+}
+
+// Error ...
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s: %s", e.CommandID, e.CommandStatus.Error())
+}
+
+// Unwrap allows errors.Is(err, pdu.ESME_RTHROTTLED)
+func (e *StatusError) Unwrap() error {
+	return e.CommandStatus
+}
+
+// BindState ...
+type BindState int32
+
+const (
+	StateOpen BindState = iota
+	StateBoundTX
+	StateBoundRX
+	StateBoundTRX
+)
+
+// String ...
+func (s BindState) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateBoundTX:
+		return "bound_tx"
+	case StateBoundRX:
+		return "bound_rx"
+	case StateBoundTRX:
+		return "bound_trx"
+	}
+	return "unknown"
+}
+
+// HandlerFunc handles a request PDU sent by the peer. A nil resp is replaced by
+// the request's default Resp(), a non-zero status is written to the response header.
+type HandlerFunc func(c *Conn, p interface{}) (resp interface{}, status pdu.CommandStatus)
+
+// Conn is an SMPP connection that correlates responses with requests by sequence number
+type Conn struct {
+	conn     net.Conn
+	settings Settings
+	handler  func(c *Conn, p interface{}, header *pdu.Header)
+
+	lastRead int64
+	state    int32
+	closing  int32
+	sequence int32
+	window   *window
+	handlers counter
+	profile  atomic.Value
+	wmu      sync.Mutex
+
+	closed    chan struct{}
+	closeOnce sync.Once
+	err       error
+}
+
+func newConn(conn net.Conn, settings Settings, handler func(c *Conn, p interface{}, header *pdu.Header)) *Conn {
+	return &Conn{
+		conn:     conn,
+		settings: settings,
+		handler:  handler,
+		window:   newWindow(settings),
+		closed:   make(chan struct{}),
+	}
+}
+
+// RemoteAddr ...
+func (c *Conn) RemoteAddr() net.Addr {
+	return c.conn.RemoteAddr()
+}
+
+// BindState ...
+func (c *Conn) BindState() BindState {
+	return BindState(atomic.LoadInt32(&c.state))
+}
+
+func (c *Conn) setBindState(state BindState) {
+	atomic.StoreInt32(&c.state, int32(state))
+}
+
+// Profile returns the vendor TLV profile selected for the bind, nil means standard TLVs only
+func (c *Conn) Profile() *pdu.VendorProfile {
+	p, _ := c.profile.Load().(*pdu.VendorProfile)
+	return p
+}
+
+func (c *Conn) setProfile(p *pdu.VendorProfile) {
+	c.profile.Store(p)
+}
+
+// Done is closed when the connection is closed
+func (c *Conn) Done() <-chan struct{} {
+	return c.closed
+}
+
+// Err returns the reason the connection was closed
+func (c *Conn) Err() error {
+	select {
+	case <-c.closed:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+func (c *Conn) closeWithError(err error) (cerr error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		cerr = c.conn.Close()
+		close(c.closed)
+		c.emit(Event{Type: EventClosed, Err: err})
+	})
+	return
+}
+
+// Send writes the request with the next sequence number and waits for its response.
+// The request occupies a window slot until the response arrives or expires.
+func (c *Conn) Send(ctx context.Context, req interface{}) (interface{}, error) {
+	return c.send(ctx, req, true)
+}
+
+// send is Send for internal requests, which may bypass the window
+func (c *Conn) send(ctx context.Context, req interface{}, slot bool) (interface{}, error) {
+	if slot {
+		if c.Closing() {
+			return nil, ErrClosing
+		}
+		if err := c.window.acquire(ctx, c.closed); err != nil {
+			return nil, err
+		}
+	}
+
+	seq := c.nextSequence()
+	pdu.WriteSequence(req, seq)
+	e := c.window.add(seq, req, slot)
+
+	if err := c.Write(req); err != nil {
+		c.window.take(seq)
+		return nil, err
+	}
+
+	select {
+	case r := <-e.done:
+		if r.err != nil {
+			return nil, r.err
+		}
+		if status := pdu.ReadCommandStatus(r.resp); status != pdu.ESME_ROK {
+			return r.resp, &StatusError{CommandID: pdu.ReadCommandID(req), CommandStatus: status}
+		}
+		return r.resp, nil
+	case <-ctx.Done():
+		c.window.take(seq)
+		return nil, ctx.Err()
+	case <-c.closed:
+		c.window.take(seq)
+		return nil, c.err
+	}
+}
+
+// InFlight returns the number of requests waiting for a response
+func (c *Conn) InFlight() int {
+	return c.window.Len()
+}
+
+// Write marshals a single PDU to the socket
+func (c *Conn) Write(packet interface{}) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+
+	select {
+	case <-c.closed:
+		return c.err
+	default:
+	}
+
+	if perr := pdu.WritePDU(c.conn, c.packing(packet)); perr != nil {
+		return perr
+	}
+	return nil
+}
+
+// packing returns packet with the short message packed as the settings want, a shallow copy
+// is changed so that the caller's packet, e.g. one kept for OnExpire, is left alone
+func (c *Conn) packing(packet interface{}) interface{} {
+	if m := pdu.ReadShortMessage(packet); m == nil || m.Packed == c.settings.GSM7Packed {
+		return packet
+	}
+	p := reflect.ValueOf(packet).Elem()
+	clone := reflect.New(p.Type())
+	clone.Elem().Set(p)
+	packet = clone.Interface()
+
+	m := pdu.ReadShortMessage(packet)
+	if c.settings.GSM7Packed {
+		m.Pack()
+	} else {
+		m.Unpack()
+	}
+	return packet
+}
+
+func (c *Conn) nextSequence() int32 {
+	for {
+		seq := atomic.AddInt32(&c.sequence, 1)
+		if seq > 0 {
+			return seq
+		}
+		// sequence_number is limited to 0x7FFFFFFF, start over
+		atomic.CompareAndSwapInt32(&c.sequence, seq, 0)
+	}
+}
+
+// serve reads PDUs until the connection is closed
+func (c *Conn) serve() {
+	log := logrus.WithFields(logrus.Fields{"worker": "session.conn", "remote": c.conn.RemoteAddr().String()})
+
+	c.touch()
+	if c.settings.EnquireLinkInterval > 0 {
+		go c.keepalive()
+	}
+
+	r := bufio.NewReader(c.conn)
+	for {
+		frame, header, err := pdu.ReadFrame(r)
+		if err != nil {
+			// the frame could not be read, the socket is gone
+			if c.Closing() {
+				err = ErrClosed
+			}
+			c.closeWithError(err)
+			return
+		}
+
+		p, _, perr := pdu.DecodePDU(frame)
+		if perr != nil {
+			// the frame was consumed entirely, so the stream stays in sync
+			log.Warnf("Can't decode %s: %s", header.CommandID, perr.Error())
+			if _, ok := p.(pdu.Responsable); ok && header.CommandID&respBit == 0 {
+				// a malformed UDH, the request is answered with ESME_RINVMSGLEN
+				c.respond(p, status(perr.CommandStatus))
+			} else {
+				c.nack(header.Sequence, perr.CommandStatus)
+			}
+			continue
+		}
+
+		c.touch()
+
+		if m := pdu.ReadShortMessage(p); m != nil {
+			m.Packed = c.settings.GSM7Packed
+		}
+
+		profile := c.Profile()
+		if logrus.IsLevelEnabled(logrus.DebugLevel) {
+			log.Debugf("Received %s with sequence %d: %s", header.CommandID, header.Sequence, pdu.ReadTags(p).Format(profile))
+		}
+
+		if header.CommandID&respBit != 0 {
+			if !c.window.deliver(header.Sequence, p) {
+				log.Warnf("Unexpected %s with sequence %d", header.CommandID, header.Sequence)
+			}
+			continue
+		}
+
+		if err := profile.Validate(pdu.ReadTags(p)); err != nil {
+			log.Warnf("Invalid TLV in %s: %s", header.CommandID, err.Error())
+			st := pdu.ESME_RINVOPTPARAMVAL
+			errors.As(err, &st)
+			c.respond(p, status(st))
+			continue
+		}
+
+		switch req := p.(type) {
+		case *pdu.EnquireLink:
+			c.respond(p, nil)
+			continue
+		case *pdu.Unbind:
+			c.unbound(req)
+			continue
+		}
+
+		c.handler(c, p, header)
+	}
+}
+
+// respond dispatches the request to fn and writes the response with the request sequence
+func (c *Conn) respond(p interface{}, fn HandlerFunc) {
+	var resp interface{}
+	var status pdu.CommandStatus
+	if fn != nil {
+		resp, status = fn(c, p)
+	}
+	if resp == nil {
+		r, ok := p.(pdu.Responsable)
+		if !ok {
+			if status != pdu.ESME_ROK {
+				c.nack(pdu.ReadSequence(p), status)
+			}
+			return
+		}
+		resp = r.Resp()
+	}
+	pdu.WriteSequence(resp, pdu.ReadSequence(p))
+	if status != pdu.ESME_ROK {
+		pdu.WriteCommandStatus(resp, status)
+	}
+	if err := c.Write(resp); err != nil {
+		logrus.WithFields(logrus.Fields{"worker": "session.conn"}).Errorf("Can't write response: %s", err.Error())
+	}
+}
+
+// dispatch runs the handler outside of the read loop, Close waits for it
+func (c *Conn) dispatch(p interface{}, fn HandlerFunc) {
+	c.handlers.add(1)
+	go func() {
+		defer c.handlers.add(-1)
+		c.respond(p, fn)
+	}()
+}
+
+// nack answers a PDU that could not be decoded
+func (c *Conn) nack(sequence int32, status pdu.CommandStatus) {
+	if sequence <= 0 {
+		return
+	}
+	_ = c.Write(&pdu.GenericNACK{Header: pdu.Header{Sequence: sequence, CommandStatus: status}})
+}
@@ -0,0 +1,38 @@
+package session
+
+import (
+	"bytes"
+	"net"
+	"testing"
+
+	"github.com/goldsheva/smpp-lib/coding"
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+func TestConnWriteKeepsPacket(t *testing.T) {
+	local, remote := net.Pipe()
+	defer local.Close()
+	defer remote.Close()
+	c := newConn(local, Settings{GSM7Packed: true}, nil)
+
+	p := &pdu.SubmitSM{Header: pdu.Header{Sequence: 1}}
+	p.ShortMessage.DataCoding = coding.GSM7BitCoding
+	p.ShortMessage.Message = []byte("hello")
+
+	done := make(chan error, 1)
+	go func() { done <- c.Write(p) }()
+	frame, _, err := pdu.ReadFrame(remote)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	if p.ShortMessage.Packed || string(p.ShortMessage.Message) != "hello" {
+		t.Errorf("caller's short message changed to %+v", p.ShortMessage)
+	}
+	if packed := coding.PackGSM7([]byte("hello"), 0); !bytes.HasSuffix(frame, packed) {
+		t.Errorf("frame %x doesn't end with the packed message %x", frame, packed)
+	}
+}
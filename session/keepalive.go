@@ -0,0 +1,100 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrLinkDead = errors.New("LinkDead")
+
+// EventType ...
+type EventType int
+
+const (
+	// EventLinkDead is emitted when enquire_link_resp did not arrive in time
+	EventLinkDead EventType = iota + 1
+	// EventClosed is emitted once the socket is closed, Event.Err holds the reason
+	EventClosed
+)
+
+// String ...
+func (t EventType) String() string {
+	switch t {
+	case EventLinkDead:
+		return "link_dead"
+	case EventClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// Event is a connection lifecycle notification
+type Event struct {
+	Type EventType
+	Err  error
+}
+
+func (c *Conn) emit(ev Event) {
+	if c.settings.OnEvent != nil {
+		c.settings.OnEvent(c, ev)
+	}
+}
+
+// touch records inbound activity
+func (c *Conn) touch() {
+	atomic.StoreInt64(&c.lastRead, time.Now().UnixNano())
+}
+
+func (c *Conn) idle() time.Duration {
+	return time.Since(time.Unix(0, atomic.LoadInt64(&c.lastRead)))
+}
+
+// keepalive sends enquire_link when nothing was received for EnquireLinkInterval
+// and closes the connection when the peer stops answering.
+func (c *Conn) keepalive() {
+	interval := c.settings.EnquireLinkInterval
+	timeout := c.settings.EnquireLinkTimeout
+	if timeout <= 0 {
+		timeout = interval
+	}
+
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-timer.C:
+		}
+
+		if idle := c.idle(); idle < interval {
+			timer.Reset(interval - idle)
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		_, err := c.send(ctx, &pdu.EnquireLink{}, false)
+		cancel()
+
+		var se *StatusError
+		if err != nil && !errors.As(err, &se) {
+			select {
+			case <-c.closed:
+				return
+			default:
+			}
+			logrus.WithFields(logrus.Fields{"worker": "session.keepalive", "remote": c.conn.RemoteAddr().String()}).
+				Warnf("No enquire_link_resp within %s: %s", timeout, err.Error())
+			c.emit(Event{Type: EventLinkDead, Err: err})
+			_ = c.closeWithError(ErrLinkDead)
+			return
+		}
+		timer.Reset(interval)
+	}
+}
@@ -0,0 +1,67 @@
+package session
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+func TestKeepaliveClosesDeadLink(t *testing.T) {
+	events := make(chan Event, 4)
+	c, p := bindClient(t, Config{Settings: Settings{
+		EnquireLinkInterval: 20 * time.Millisecond,
+		EnquireLinkTimeout:  20 * time.Millisecond,
+		OnEvent:             func(c *Conn, ev Event) { events <- ev },
+	}})
+
+	// an answered enquire_link keeps the link up
+	start := time.Now()
+	req, ok := p.read().(*pdu.EnquireLink)
+	if !ok {
+		t.Fatalf("got %T, want enquire_link", req)
+	}
+	if idle := time.Since(start); idle > 500*time.Millisecond {
+		t.Errorf("enquire_link after %s", idle)
+	}
+	p.answer(req, pdu.ESME_ROK)
+
+	// the next one stays unanswered
+	if _, ok = p.read().(*pdu.EnquireLink); !ok {
+		t.Fatal("no second enquire_link")
+	}
+	select {
+	case <-c.Done():
+	case <-time.After(time.Second):
+		t.Fatal("the dead link is still open")
+	}
+	if !errors.Is(c.Err(), ErrLinkDead) {
+		t.Errorf("Err %v, want ErrLinkDead", c.Err())
+	}
+	if ev := <-events; ev.Type != EventLinkDead {
+		t.Errorf("first event %s, want link_dead", ev.Type)
+	}
+	if ev := <-events; ev.Type != EventClosed || !errors.Is(ev.Err, ErrLinkDead) {
+		t.Errorf("second event %s %v, want closed", ev.Type, ev.Err)
+	}
+}
+
+func TestKeepaliveIdleOnly(t *testing.T) {
+	c, p := bindClient(t, Config{Settings: Settings{EnquireLinkInterval: 50 * time.Millisecond}})
+
+	// inbound traffic within the interval defers enquire_link
+	deadline := time.Now().Add(150 * time.Millisecond)
+	for time.Now().Before(deadline) {
+		if err := p.write(&pdu.EnquireLink{Header: pdu.Header{Sequence: 1}}); err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := p.read().(*pdu.EnquireLinkResp); !ok {
+			t.Fatal("got a request of the client on a busy link")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if c.Err() != nil {
+		t.Errorf("link closed: %v", c.Err())
+	}
+}
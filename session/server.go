@@ -0,0 +1,258 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrServerClosed = errors.New("ServerClosed")
+
+// BindRequest holds the fields shared by bind_transmitter, bind_receiver and bind_transceiver
+type BindRequest struct {
+	Type       BindType
+	SystemID   string
+	Password   string
+	SystemType string
+	Version    pdu.InterfaceVersion
+	TON        byte
+	NPI        byte
+	AddrRange  string
+}
+
+// Server is an MC that accepts ESME binds and dispatches their requests to handlers
+type Server struct {
+	// SystemID is returned in bind responses
+	SystemID string
+
+	// Settings applies to every accepted connection
+	Settings Settings
+
+	// Auth validates a bind request, a nil Auth accepts every bind
+	Auth func(c *Conn, bind BindRequest) pdu.CommandStatus
+
+	// Profile selects the vendor TLV profile of an accepted bind, optional
+	Profile func(bind BindRequest) *pdu.VendorProfile
+
+	mu        sync.Mutex
+	handlers  map[pdu.CommandID]HandlerFunc
+	listeners map[net.Listener]struct{}
+	conns     map[*Conn]struct{}
+	closed    bool
+}
+
+// NewServer ...
+func NewServer(systemID string) *Server {
+	return &Server{
+		SystemID:  systemID,
+		handlers:  make(map[pdu.CommandID]HandlerFunc),
+		listeners: make(map[net.Listener]struct{}),
+		conns:     make(map[*Conn]struct{}),
+	}
+}
+
+// Handle registers fn for the PDU type of packet, e.g. s.Handle(&pdu.SubmitSM{}, fn)
+func (s *Server) Handle(packet interface{}, fn HandlerFunc) {
+	id := pdu.CommandIDOf(packet)
+	if id == 0 {
+		panic("session: unknown PDU type")
+	}
+	s.mu.Lock()
+	s.handlers[id] = fn
+	s.mu.Unlock()
+}
+
+// Serve accepts connections on l until it fails or the server is closed
+func (s *Server) Serve(l net.Listener) error {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return ErrServerClosed
+	}
+	s.listeners[l] = struct{}{}
+	s.mu.Unlock()
+
+	defer func() {
+		s.mu.Lock()
+		delete(s.listeners, l)
+		s.mu.Unlock()
+	}()
+
+	for {
+		nc, err := l.Accept()
+		if err != nil {
+			s.mu.Lock()
+			closed := s.closed
+			s.mu.Unlock()
+			if closed {
+				return ErrServerClosed
+			}
+			return err
+		}
+
+		c := newConn(nc, s.Settings, s.handle)
+		s.mu.Lock()
+		s.conns[c] = struct{}{}
+		s.mu.Unlock()
+
+		go func() {
+			c.serve()
+			s.mu.Lock()
+			delete(s.conns, c)
+			s.mu.Unlock()
+		}()
+	}
+}
+
+// Close stops all listeners and drops every connection
+func (s *Server) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		if cerr := l.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	conns := make([]*Conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	for _, c := range conns {
+		_ = c.closeWithError(ErrServerClosed)
+	}
+	return err
+}
+
+// Shutdown stops all listeners and gracefully unbinds every connection, see Conn.Close
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.mu.Lock()
+	s.closed = true
+	var err error
+	for l := range s.listeners {
+		if cerr := l.Close(); cerr != nil && err == nil {
+			err = cerr
+		}
+	}
+	conns := make([]*Conn, 0, len(s.conns))
+	for c := range s.conns {
+		conns = append(conns, c)
+	}
+	s.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, c := range conns {
+		wg.Add(1)
+		go func(c *Conn) {
+			defer wg.Done()
+			_ = c.Close(ctx)
+		}(c)
+	}
+	wg.Wait()
+	return err
+}
+
+// handle routes a request PDU according to the bind state of the connection
+func (s *Server) handle(c *Conn, p interface{}, header *pdu.Header) {
+	switch req := p.(type) {
+	case *pdu.BindTransmitter:
+		s.bind(c, p, BindRequest{Transmitter, req.SystemID, req.Password, req.SystemType, req.Version, req.TON, req.NPI, req.AddrRange})
+		return
+	case *pdu.BindReceiver:
+		s.bind(c, p, BindRequest{Receiver, req.SystemID, req.Password, req.SystemType, req.Version, req.TON, req.NPI, req.AddrRange})
+		return
+	case *pdu.BindTransceiver:
+		s.bind(c, p, BindRequest{Transceiver, req.SystemID, req.Password, req.SystemType, req.Version, req.TON, req.NPI, req.AddrRange})
+		return
+	}
+
+	if !allowed(c.BindState(), p) {
+		c.respond(p, status(pdu.ESME_RINVBNDSTS))
+		return
+	}
+	if st := validateTimes(p); st != pdu.ESME_ROK {
+		c.respond(p, status(st))
+		return
+	}
+
+	s.mu.Lock()
+	fn, ok := s.handlers[header.CommandID]
+	s.mu.Unlock()
+	if !ok {
+		logrus.WithFields(logrus.Fields{"worker": "session.server"}).Warnf("No handler for %s", header.CommandID)
+		c.nack(header.Sequence, pdu.ESME_RINVCMDID)
+		return
+	}
+
+	c.dispatch(p, fn)
+}
+
+func (s *Server) bind(c *Conn, p interface{}, req BindRequest) {
+	if c.BindState() != StateOpen {
+		c.respond(p, status(pdu.ESME_RALYBND))
+		return
+	}
+
+	var st pdu.CommandStatus
+	if s.Auth != nil {
+		st = s.Auth(c, req)
+	}
+
+	resp := p.(pdu.Responsable).Resp()
+	switch r := resp.(type) {
+	case *pdu.BindTransmitterResp:
+		r.SystemID = s.SystemID
+	case *pdu.BindReceiverResp:
+		r.SystemID = s.SystemID
+	case *pdu.BindTransceiverResp:
+		r.SystemID = s.SystemID
+	}
+	if st == pdu.ESME_ROK {
+		if s.Profile != nil {
+			c.setProfile(s.Profile(req))
+		}
+		c.setBindState(req.Type.state())
+	}
+	c.respond(p, func(*Conn, interface{}) (interface{}, pdu.CommandStatus) {
+		return resp, st
+	})
+}
+
+// allowed reports whether an ESME may send the request in the given bind state
+func allowed(state BindState, p interface{}) bool {
+	switch p.(type) {
+	case *pdu.SubmitSM, *pdu.SubmitMulti, *pdu.DataSM,
+		*pdu.QuerySM, *pdu.CancelSM, *pdu.ReplaceSM,
+		*pdu.BroadcastSM, *pdu.QueryBroadcastSM, *pdu.CancelBroadcastSM:
+		return state == StateBoundTX || state == StateBoundTRX
+	}
+	return false
+}
+
+// validateTimes checks schedule_delivery_time and validity_period of the requests that carry them
+func validateTimes(p interface{}) pdu.CommandStatus {
+	switch req := p.(type) {
+	case *pdu.SubmitSM:
+		return pdu.ValidateTimes(req.ScheduleDeliveryTime, req.ValidityPeriod)
+	case *pdu.SubmitMulti:
+		return pdu.ValidateTimes(req.ScheduleDeliveryTime, req.ValidityPeriod)
+	case *pdu.ReplaceSM:
+		return pdu.ValidateTimes(req.ScheduleDeliveryTime, req.ValidityPeriod)
+	case *pdu.BroadcastSM:
+		return pdu.ValidateTimes(req.ScheduleDeliveryTime, req.ValidityPeriod)
+	}
+	return pdu.ESME_ROK
+}
+
+// status returns a handler that answers with the default response and the given status
+func status(st pdu.CommandStatus) HandlerFunc {
+	return func(*Conn, interface{}) (interface{}, pdu.CommandStatus) {
+		return nil, st
+	}
+}
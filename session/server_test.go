@@ -0,0 +1,133 @@
+package session
+
+import (
+	"net"
+	"testing"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+// pipeListener hands out the server ends of net.Pipe connections
+type pipeListener struct {
+	conns  chan net.Conn
+	closed chan struct{}
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), closed: make(chan struct{})}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.closed:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	select {
+	case <-l.closed:
+	default:
+		close(l.closed)
+	}
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr {
+	return &net.UnixAddr{Name: "pipe", Net: "pipe"}
+}
+
+// dial connects a new peer to the server
+func (l *pipeListener) dial(t *testing.T) *peer {
+	local, remote := net.Pipe()
+	t.Cleanup(func() {
+		_ = local.Close()
+		_ = remote.Close()
+	})
+	l.conns <- remote
+	return &peer{t: t, conn: local}
+}
+
+func serve(t *testing.T, s *Server) *pipeListener {
+	l := newPipeListener()
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(func() { _ = s.Close() })
+	return l
+}
+
+func TestServerRoutesByBindState(t *testing.T) {
+	s := NewServer("mc")
+	s.Handle(&pdu.SubmitSM{}, func(c *Conn, p interface{}) (interface{}, pdu.CommandStatus) {
+		resp := p.(*pdu.SubmitSM).Resp().(*pdu.SubmitSMResp)
+		resp.MessageID = "m1"
+		return resp, pdu.ESME_ROK
+	})
+	p := serve(t, s).dial(t)
+
+	// requests before the bind
+	if err := p.write(&pdu.SubmitSM{Header: pdu.Header{Sequence: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, ok := p.read().(*pdu.SubmitSMResp); !ok || resp.Header.CommandStatus != pdu.ESME_RINVBNDSTS || resp.Header.Sequence != 1 {
+		t.Fatalf("unbound submit_sm: got %+v", resp)
+	}
+	if err := p.write(&pdu.Unbind{Header: pdu.Header{Sequence: 2}}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, ok := p.read().(*pdu.UnbindResp); !ok || resp.Header.CommandStatus != pdu.ESME_RINVBNDSTS {
+		t.Fatalf("unbound unbind: got %+v", resp)
+	}
+
+	if err := p.write(&pdu.BindTransmitter{Header: pdu.Header{Sequence: 3}, SystemID: "esme", Version: pdu.SMPPVersion34}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, ok := p.read().(*pdu.BindTransmitterResp); !ok || resp.Header.CommandStatus != pdu.ESME_ROK || resp.SystemID != "mc" {
+		t.Fatalf("bind_transmitter: got %+v", resp)
+	}
+	if err := p.write(&pdu.BindTransceiver{Header: pdu.Header{Sequence: 4}, SystemID: "esme", Version: pdu.SMPPVersion34}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, ok := p.read().(*pdu.BindTransceiverResp); !ok || resp.Header.CommandStatus != pdu.ESME_RALYBND {
+		t.Fatalf("second bind: got %+v", resp)
+	}
+
+	for _, tt := range []struct {
+		req    interface{}
+		resp   interface{}
+		status pdu.CommandStatus
+	}{
+		{&pdu.SubmitSM{Header: pdu.Header{Sequence: 5}}, &pdu.SubmitSMResp{}, pdu.ESME_ROK},
+		// an MC sends deliver_sm, it is no request of an ESME
+		{&pdu.DeliverSM{Header: pdu.Header{Sequence: 6}}, &pdu.DeliverSMResp{}, pdu.ESME_RINVBNDSTS},
+		// allowed but without a handler
+		{&pdu.QuerySM{Header: pdu.Header{Sequence: 7}, MessageID: "m1"}, &pdu.GenericNACK{}, pdu.ESME_RINVCMDID},
+		{&pdu.EnquireLink{Header: pdu.Header{Sequence: 8}}, &pdu.EnquireLinkResp{}, pdu.ESME_ROK},
+	} {
+		if err := p.write(tt.req); err != nil {
+			t.Fatal(err)
+		}
+		resp := p.read()
+		if pdu.CommandIDOf(resp) != pdu.CommandIDOf(tt.resp) || pdu.ReadCommandStatus(resp) != tt.status || pdu.ReadSequence(resp) != pdu.ReadSequence(tt.req) {
+			t.Errorf("%T: got %T %s sequence %d, want %T %s", tt.req, resp, pdu.ReadCommandStatus(resp), pdu.ReadSequence(resp), tt.resp, tt.status)
+		}
+	}
+}
+
+func TestServerNacksUnknownCommand(t *testing.T) {
+	p := serve(t, NewServer("mc")).dial(t)
+
+	frame, perr := pdu.AppendPDU(nil, &pdu.EnquireLink{Header: pdu.Header{Sequence: 9}})
+	if perr != nil {
+		t.Fatal(perr)
+	}
+	frame[7] = 0x77 // command_id 0x00000077
+	if _, err := p.conn.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+	nack, ok := p.read().(*pdu.GenericNACK)
+	if !ok || nack.Header.CommandStatus != pdu.ESME_RINVCMDID || nack.Header.Sequence != 9 {
+		t.Errorf("got %+v, want generic_nack ESME_RINVCMDID", nack)
+	}
+}
@@ -0,0 +1,27 @@
+package session
+
+import (
+	"time"
+)
+
+// Settings controls flow control and keepalive of a connection
+type Settings struct {
+	// WindowSize is the maximum number of requests in flight, zero means unlimited
+	WindowSize int
+	// WindowWait makes Send block until a slot is free instead of failing with ErrWindowFull
+	WindowWait bool
+	// ResponseTimeout expires requests whose response never arrives, zero disables it
+	ResponseTimeout time.Duration
+	// OnExpire receives every expired request, e.g. the original *pdu.SubmitSM to retry it
+	OnExpire func(req interface{})
+
+	// EnquireLinkInterval is the idle time after which enquire_link is sent, zero disables it
+	EnquireLinkInterval time.Duration
+	// EnquireLinkTimeout declares the link dead when enquire_link_resp is late, defaults to EnquireLinkInterval
+	EnquireLinkTimeout time.Duration
+	// GSM7Packed sends GSM7 short_message as packed septets and reads received ones as such,
+	// otherwise one septet per octet is used
+	GSM7Packed bool
+	// OnEvent receives connection lifecycle events
+	OnEvent func(c *Conn, ev Event)
+}
@@ -0,0 +1,210 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+	"github.com/sirupsen/logrus"
+)
+
+var ErrNotBound = errors.New("NotBound")
+
+// LinkState is the state of a supervised session
+type LinkState int
+
+const (
+	LinkConnecting LinkState = iota + 1
+	LinkBound
+	LinkDisconnected
+	LinkUnbinding
+	LinkClosed
+)
+
+// String ...
+func (s LinkState) String() string {
+	switch s {
+	case LinkConnecting:
+		return "connecting"
+	case LinkBound:
+		return "bound"
+	case LinkDisconnected:
+		return "disconnected"
+	case LinkUnbinding:
+		return "unbinding"
+	case LinkClosed:
+		return "closed"
+	}
+	return "unknown"
+}
+
+// Backoff is a jittered exponential delay between rebind attempts
+type Backoff struct {
+	Initial    time.Duration // first delay, defaults to 1s
+	Max        time.Duration // upper bound of the delay, defaults to 1m
+	Multiplier float64       // growth factor, defaults to 2
+	Jitter     float64       // randomised fraction of the delay in [0, 1]
+	// MaxAttempts stops retrying after that many consecutive failures, zero retries forever
+	MaxAttempts int
+}
+
+// Delay returns the wait before the given attempt, counted from zero
+func (b Backoff) Delay(attempt int) time.Duration {
+	initial, max, multiplier := b.Initial, b.Max, b.Multiplier
+	if initial <= 0 {
+		initial = time.Second
+	}
+	if max <= 0 {
+		max = time.Minute
+	}
+	if multiplier < 1 {
+		multiplier = 2
+	}
+
+	delay := float64(initial)
+	for i := 0; i < attempt && delay < float64(max); i++ {
+		delay *= multiplier
+	}
+	if delay > float64(max) {
+		delay = float64(max)
+	}
+	if b.Jitter > 0 {
+		jitter := b.Jitter
+		if jitter > 1 {
+			jitter = 1
+		}
+		delay -= delay * jitter * rand.Float64()
+	}
+	return time.Duration(delay)
+}
+
+// Retryable reports whether a bind failure is worth another attempt.
+// Rejected credentials are permanent, everything else is treated as transient.
+func Retryable(err error) bool {
+	return !errors.Is(err, pdu.ESME_RINVPASWD) &&
+		!errors.Is(err, pdu.ESME_RINVSYSID) &&
+		!errors.Is(err, pdu.ESME_RINVSYSTYP)
+}
+
+// Supervisor keeps a Client bound, rebinding with backoff when the link drops
+type Supervisor struct {
+	Config  Config
+	Backoff Backoff
+
+	// OnStateChange receives every state transition, err holds its cause if any
+	OnStateChange func(state LinkState, err error)
+
+	mu     sync.Mutex
+	state  LinkState
+	client *Client
+}
+
+// NewSupervisor ...
+func NewSupervisor(conf Config, backoff Backoff) *Supervisor {
+	return &Supervisor{Config: conf, Backoff: backoff}
+}
+
+// State ...
+func (s *Supervisor) State() LinkState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state
+}
+
+// Client returns the currently bound client or nil
+func (s *Supervisor) Client() *Client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+// Submit sends submit_sm over the current bind
+func (s *Supervisor) Submit(ctx context.Context, p *pdu.SubmitSM) (*pdu.SubmitSMResp, error) {
+	c := s.Client()
+	if c == nil {
+		return nil, ErrNotBound
+	}
+	return c.Submit(ctx, p)
+}
+
+// Run binds and rebinds until ctx is done, a bind fails permanently or
+// Backoff.MaxAttempts is exhausted.
+func (s *Supervisor) Run(ctx context.Context) error {
+	log := logrus.WithFields(logrus.Fields{"worker": "session.supervisor", "addr": s.Config.Addr})
+
+	var attempt int
+	for {
+		s.setState(LinkConnecting, nil, nil)
+		c, err := Dial(ctx, s.Config)
+		if err != nil {
+			if ctx.Err() != nil {
+				s.setState(LinkClosed, nil, ctx.Err())
+				return ctx.Err()
+			}
+			if !Retryable(err) {
+				log.Errorf("Bind rejected: %s", err.Error())
+				s.setState(LinkClosed, nil, err)
+				return err
+			}
+			attempt++
+			if s.Backoff.MaxAttempts > 0 && attempt >= s.Backoff.MaxAttempts {
+				log.Errorf("Giving up after %d attempts: %s", attempt, err.Error())
+				s.setState(LinkClosed, nil, err)
+				return err
+			}
+			s.setState(LinkDisconnected, nil, err)
+			if !s.sleep(ctx, s.Backoff.Delay(attempt-1)) {
+				s.setState(LinkClosed, nil, ctx.Err())
+				return ctx.Err()
+			}
+			continue
+		}
+
+		attempt = 0
+		s.setState(LinkBound, c, nil)
+
+		select {
+		case <-c.Done():
+			log.Warnf("Link lost: %v", c.Err())
+			s.setState(LinkDisconnected, nil, c.Err())
+			if !s.sleep(ctx, s.Backoff.Delay(0)) {
+				s.setState(LinkClosed, nil, ctx.Err())
+				return ctx.Err()
+			}
+		case <-ctx.Done():
+			s.setState(LinkUnbinding, nil, nil)
+			uctx, cancel := context.WithTimeout(context.Background(), unbindTimeout)
+			_ = c.Close(uctx)
+			cancel()
+			s.setState(LinkClosed, nil, ctx.Err())
+			return ctx.Err()
+		}
+	}
+}
+
+// unbindTimeout bounds the drain on shutdown and, without a ResponseTimeout, the wait for unbind_resp
+const unbindTimeout = 5 * time.Second
+
+func (s *Supervisor) setState(state LinkState, c *Client, err error) {
+	s.mu.Lock()
+	s.state = state
+	s.client = c
+	s.mu.Unlock()
+	if s.OnStateChange != nil {
+		s.OnStateChange(state, err)
+	}
+}
+
+func (s *Supervisor) sleep(ctx context.Context, d time.Duration) bool {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,121 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+// listenMC starts an MC on a loopback port that answers every bind with status
+func listenMC(t *testing.T, status pdu.CommandStatus, binds *int32) string {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("no loopback listener: %v", err)
+	}
+	s := NewServer("mc")
+	s.Auth = func(c *Conn, bind BindRequest) pdu.CommandStatus {
+		atomic.AddInt32(binds, 1)
+		return status
+	}
+	go func() { _ = s.Serve(l) }()
+	t.Cleanup(func() { _ = s.Close() })
+	return l.Addr().String()
+}
+
+func TestSupervisorStopsOnRejectedPassword(t *testing.T) {
+	var binds int32
+	addr := listenMC(t, pdu.ESME_RINVPASWD, &binds)
+	s := NewSupervisor(Config{Addr: addr, SystemID: "esme", Password: "wrong"}, Backoff{Initial: time.Millisecond, MaxAttempts: 5})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err := s.Run(ctx)
+	if !errors.Is(err, pdu.ESME_RINVPASWD) {
+		t.Errorf("Run: got %v, want ESME_RINVPASWD", err)
+	}
+	if n := atomic.LoadInt32(&binds); n != 1 {
+		t.Errorf("%d binds, want 1", n)
+	}
+	if s.State() != LinkClosed {
+		t.Errorf("state %s, want closed", s.State())
+	}
+}
+
+func TestSupervisorRetriesBindFailure(t *testing.T) {
+	var binds int32
+	addr := listenMC(t, pdu.ESME_RBINDFAIL, &binds)
+	var states []LinkState
+	s := NewSupervisor(Config{Addr: addr, SystemID: "esme"}, Backoff{Initial: time.Millisecond, Max: 5 * time.Millisecond, MaxAttempts: 3})
+	s.OnStateChange = func(state LinkState, err error) { states = append(states, state) }
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	err := s.Run(ctx)
+	if !errors.Is(err, pdu.ESME_RBINDFAIL) {
+		t.Errorf("Run: got %v, want ESME_RBINDFAIL", err)
+	}
+	if n := atomic.LoadInt32(&binds); n != 3 {
+		t.Errorf("%d binds, want 3", n)
+	}
+	want := []LinkState{LinkConnecting, LinkDisconnected, LinkConnecting, LinkDisconnected, LinkConnecting, LinkClosed}
+	if len(states) != len(want) {
+		t.Fatalf("states %v, want %v", states, want)
+	}
+	for i := range want {
+		if states[i] != want[i] {
+			t.Fatalf("states %v, want %v", states, want)
+		}
+	}
+}
+
+func TestSupervisorRebindsAndUnbinds(t *testing.T) {
+	var binds int32
+	addr := listenMC(t, pdu.ESME_ROK, &binds)
+	bound := make(chan struct{}, 4)
+	s := NewSupervisor(Config{Addr: addr, SystemID: "esme"}, Backoff{Initial: time.Millisecond})
+	s.OnStateChange = func(state LinkState, err error) {
+		if state == LinkBound {
+			bound <- struct{}{}
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.Run(ctx) }()
+
+	<-bound
+	// the link drops, the supervisor binds again
+	_ = s.Client().closeWithError(errors.New("dropped"))
+	select {
+	case <-bound:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no rebind")
+	}
+	cancel()
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Errorf("Run: got %v", err)
+	}
+	if n := atomic.LoadInt32(&binds); n != 2 {
+		t.Errorf("%d binds, want 2", n)
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	b := Backoff{Initial: 10 * time.Millisecond, Max: 50 * time.Millisecond, Multiplier: 2}
+	for attempt, want := range []time.Duration{10, 20, 40, 50, 50} {
+		if got := b.Delay(attempt); got != want*time.Millisecond {
+			t.Errorf("attempt %d: %s, want %s", attempt, got, want*time.Millisecond)
+		}
+	}
+	b.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := b.Delay(1); got < 10*time.Millisecond || got > 20*time.Millisecond {
+			t.Fatalf("jittered delay %s out of [10ms, 20ms]", got)
+		}
+	}
+}
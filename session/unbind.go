@@ -0,0 +1,122 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	ErrClosing = errors.New("Closing")
+	ErrUnbound = errors.New("Unbound")
+)
+
+// Close stops accepting new requests, waits until the requests in flight are
+// answered or ctx is done, then unbinds and closes the socket. The wait for
+// unbind_resp has a timeout of its own, ResponseTimeout or unbindTimeout, so that
+// it still happens after ctx is done.
+func (c *Conn) Close(ctx context.Context) error {
+	if !atomic.CompareAndSwapInt32(&c.closing, 0, 1) {
+		<-c.closed
+		return nil
+	}
+
+	if err := c.drain(ctx); err != nil {
+		logrus.WithFields(logrus.Fields{"worker": "session.unbind", "remote": c.conn.RemoteAddr().String()}).
+			Warnf("Closing with %d requests in flight: %s", c.window.Len(), err.Error())
+	}
+
+	if c.BindState() != StateOpen {
+		timeout := c.settings.ResponseTimeout
+		if timeout <= 0 {
+			timeout = unbindTimeout
+		}
+		uctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if _, err := c.send(uctx, &pdu.Unbind{}, false); err != nil {
+			logrus.WithFields(logrus.Fields{"worker": "session.unbind", "remote": c.conn.RemoteAddr().String()}).
+				Warnf("No unbind_resp: %s", err.Error())
+		}
+	}
+	return c.closeWithError(ErrClosed)
+}
+
+// Closing reports whether the connection no longer accepts new requests
+func (c *Conn) Closing() bool {
+	return atomic.LoadInt32(&c.closing) == 1
+}
+
+// drain waits for the requests in flight and the running handlers
+func (c *Conn) drain(ctx context.Context) error {
+	if err := c.handlers.wait(ctx, c.closed); err != nil {
+		return err
+	}
+	return c.window.wait(ctx, c.closed)
+}
+
+// unbound answers an unbind sent by the peer once the requests in flight are done
+func (c *Conn) unbound(p *pdu.Unbind) {
+	if c.BindState() == StateOpen {
+		c.respond(p, status(pdu.ESME_RINVBNDSTS))
+		return
+	}
+	atomic.StoreInt32(&c.closing, 1)
+
+	go func() {
+		ctx := context.Background()
+		if c.settings.ResponseTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, c.settings.ResponseTimeout)
+			defer cancel()
+		}
+		if err := c.drain(ctx); err != nil {
+			logrus.WithFields(logrus.Fields{"worker": "session.unbind", "remote": c.conn.RemoteAddr().String()}).
+				Warnf("Peer unbind with %d requests in flight: %s", c.window.Len(), err.Error())
+		}
+		c.respond(p, nil)
+		_ = c.closeWithError(ErrUnbound)
+	}()
+}
+
+// counter tracks running handlers and signals when none is left
+type counter struct {
+	mu    sync.Mutex
+	n     int
+	empty chan struct{}
+}
+
+func (n *counter) add(delta int) {
+	n.mu.Lock()
+	n.n += delta
+	if n.n == 0 && n.empty != nil {
+		close(n.empty)
+		n.empty = nil
+	}
+	n.mu.Unlock()
+}
+
+func (n *counter) wait(ctx context.Context, closed <-chan struct{}) error {
+	n.mu.Lock()
+	if n.n == 0 {
+		n.mu.Unlock()
+		return nil
+	}
+	if n.empty == nil {
+		n.empty = make(chan struct{})
+	}
+	empty := n.empty
+	n.mu.Unlock()
+
+	select {
+	case <-empty:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-closed:
+		return ErrClosed
+	}
+}
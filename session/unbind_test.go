@@ -0,0 +1,93 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+func TestCloseDrainsThenUnbinds(t *testing.T) {
+	c, p := bindClient(t, Config{})
+
+	submitted := make(chan error, 1)
+	go func() {
+		_, err := c.Submit(context.Background(), submitSM("a"))
+		submitted <- err
+	}()
+	req := p.read()
+
+	closed := make(chan error, 1)
+	go func() { closed <- c.Close(context.Background()) }()
+	for !c.Closing() {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := c.Submit(context.Background(), submitSM("b")); !errors.Is(err, ErrClosing) {
+		t.Errorf("submit while closing: got %v, want ErrClosing", err)
+	}
+
+	// unbind only follows the response to the request in flight
+	p.answer(req, pdu.ESME_ROK)
+	if err := <-submitted; err != nil {
+		t.Fatalf("in-flight submit: %v", err)
+	}
+	unbind, ok := p.read().(*pdu.Unbind)
+	if !ok {
+		t.Fatalf("got %T, want unbind", unbind)
+	}
+	p.answer(unbind, pdu.ESME_ROK)
+	if err := <-closed; err != nil {
+		t.Errorf("Close: %v", err)
+	}
+	if !errors.Is(c.Err(), ErrClosed) {
+		t.Errorf("Err %v, want ErrClosed", c.Err())
+	}
+}
+
+func TestCloseUnbindsAfterDrainTimeout(t *testing.T) {
+	c, p := bindClient(t, Config{})
+
+	go func() { _, _ = c.Submit(context.Background(), submitSM("a")) }()
+	p.read() // never answered
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	closed := make(chan error, 1)
+	go func() { closed <- c.Close(ctx) }()
+
+	unbind, ok := p.read().(*pdu.Unbind)
+	if !ok {
+		t.Fatalf("got %T, want unbind", unbind)
+	}
+	// the expired drain context must not cut the wait for unbind_resp short
+	time.Sleep(10 * time.Millisecond)
+	select {
+	case err := <-closed:
+		t.Fatalf("Close returned %v before unbind_resp", err)
+	default:
+	}
+	resp := unbind.Resp()
+	pdu.WriteSequence(resp, unbind.Header.Sequence)
+	if err := p.write(resp); err != nil {
+		t.Fatalf("unbind_resp: %v", err)
+	}
+	if err := <-closed; err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestPeerUnbind(t *testing.T) {
+	c, p := bindClient(t, Config{})
+	if err := p.write(&pdu.Unbind{Header: pdu.Header{Sequence: 1}}); err != nil {
+		t.Fatal(err)
+	}
+	if resp, ok := p.read().(*pdu.UnbindResp); !ok || resp.Header.Sequence != 1 {
+		t.Fatalf("got %+v, want unbind_resp", resp)
+	}
+	<-c.Done()
+	if !errors.Is(c.Err(), ErrUnbound) {
+		t.Errorf("Err %v, want ErrUnbound", c.Err())
+	}
+}
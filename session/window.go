@@ -0,0 +1,172 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+var ErrWindowFull = errors.New("WindowFull")
+
+// TimeoutError is returned when the response to Request did not arrive within Settings.ResponseTimeout
+type TimeoutError struct {
+	Request interface{}
+}
+
+// Error ...
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s: response timeout (sequence %d)", pdu.ReadCommandID(e.Request), pdu.ReadSequence(e.Request))
+}
+
+type result struct {
+	resp interface{}
+	err  error
+}
+
+type entry struct {
+	req   interface{}
+	slot  bool
+	done  chan result
+	timer *time.Timer
+}
+
+// window tracks outstanding requests by sequence number
+type window struct {
+	settings Settings
+	slots    chan struct{}
+
+	mu      sync.Mutex
+	entries map[int32]*entry
+	empty   chan struct{}
+}
+
+func newWindow(settings Settings) *window {
+	w := &window{
+		settings: settings,
+		entries:  make(map[int32]*entry),
+	}
+	if settings.WindowSize > 0 {
+		w.slots = make(chan struct{}, settings.WindowSize)
+	}
+	return w
+}
+
+// acquire reserves a slot for a new request
+func (w *window) acquire(ctx context.Context, closed <-chan struct{}) error {
+	if w.slots == nil {
+		return nil
+	}
+	if !w.settings.WindowWait {
+		select {
+		case w.slots <- struct{}{}:
+			return nil
+		default:
+			return ErrWindowFull
+		}
+	}
+	select {
+	case w.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-closed:
+		return ErrClosed
+	}
+}
+
+func (w *window) release() {
+	if w.slots != nil {
+		<-w.slots
+	}
+}
+
+// add registers a request, slot tells whether it holds a window slot
+func (w *window) add(seq int32, req interface{}, slot bool) *entry {
+	e := &entry{req: req, slot: slot, done: make(chan result, 1)}
+	w.mu.Lock()
+	w.entries[seq] = e
+	if w.settings.ResponseTimeout > 0 {
+		e.timer = time.AfterFunc(w.settings.ResponseTimeout, func() { w.expire(seq) })
+	}
+	w.mu.Unlock()
+	return e
+}
+
+// take removes the entry and frees its slot
+func (w *window) take(seq int32) *entry {
+	w.mu.Lock()
+	e, ok := w.entries[seq]
+	if ok {
+		delete(w.entries, seq)
+	}
+	if len(w.entries) == 0 && w.empty != nil {
+		close(w.empty)
+		w.empty = nil
+	}
+	w.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	if e.timer != nil {
+		e.timer.Stop()
+	}
+	if e.slot {
+		w.release()
+	}
+	return e
+}
+
+// deliver hands the response to the waiting request
+func (w *window) deliver(seq int32, resp interface{}) bool {
+	e := w.take(seq)
+	if e == nil {
+		return false
+	}
+	e.done <- result{resp: resp}
+	return true
+}
+
+func (w *window) expire(seq int32) {
+	e := w.take(seq)
+	if e == nil {
+		return
+	}
+	e.done <- result{err: &TimeoutError{Request: e.req}}
+	if e.slot && w.settings.OnExpire != nil {
+		w.settings.OnExpire(e.req)
+	}
+}
+
+// Len returns the number of requests in flight
+func (w *window) Len() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return len(w.entries)
+}
+
+// wait blocks until no request is in flight
+func (w *window) wait(ctx context.Context, closed <-chan struct{}) error {
+	w.mu.Lock()
+	if len(w.entries) == 0 {
+		w.mu.Unlock()
+		return nil
+	}
+	if w.empty == nil {
+		w.empty = make(chan struct{})
+	}
+	empty := w.empty
+	w.mu.Unlock()
+
+	select {
+	case <-empty:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-closed:
+		return ErrClosed
+	}
+}
@@ -0,0 +1,120 @@
+package session
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/goldsheva/smpp-lib/pdu"
+)
+
+func TestWindowFull(t *testing.T) {
+	c, p := bindClient(t, Config{Settings: Settings{WindowSize: 1}})
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.Submit(context.Background(), submitSM("a"))
+		errs <- err
+	}()
+	req := p.read()
+	if _, err := c.Submit(context.Background(), submitSM("b")); !errors.Is(err, ErrWindowFull) {
+		t.Errorf("second submit: got %v, want ErrWindowFull", err)
+	}
+
+	p.answer(req, pdu.ESME_ROK)
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	// the slot is free again
+	go func() {
+		_, err := c.Submit(context.Background(), submitSM("c"))
+		errs <- err
+	}()
+	p.answer(p.read(), pdu.ESME_ROK)
+	if err := <-errs; err != nil {
+		t.Errorf("after the response: %v", err)
+	}
+}
+
+func TestWindowWait(t *testing.T) {
+	c, p := bindClient(t, Config{Settings: Settings{WindowSize: 1, WindowWait: true}})
+
+	errs := make(chan error, 2)
+	for _, text := range []string{"a", "b"} {
+		go func(text string) {
+			_, err := c.Submit(context.Background(), submitSM(text))
+			errs <- err
+		}(text)
+	}
+	first := p.read()
+	select {
+	case err := <-errs:
+		t.Fatalf("a submit returned %v before a slot was free", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+	p.answer(first, pdu.ESME_ROK)
+	p.answer(p.read(), pdu.ESME_ROK)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; err != nil {
+			t.Error(err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	go func() {
+		_, err := c.Submit(context.Background(), submitSM("c"))
+		errs <- err
+	}()
+	held := p.read()
+	if _, err := c.Submit(ctx, submitSM("d")); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("waiting submit: got %v, want the context error", err)
+	}
+	p.answer(held, pdu.ESME_ROK)
+	<-errs
+}
+
+func TestWindowExpiry(t *testing.T) {
+	expired := make(chan interface{}, 1)
+	c, p := bindClient(t, Config{Settings: Settings{
+		WindowSize:      1,
+		ResponseTimeout: 20 * time.Millisecond,
+		OnExpire:        func(req interface{}) { expired <- req },
+	}})
+
+	req := submitSM("a")
+	errs := make(chan error, 1)
+	go func() {
+		_, err := c.Submit(context.Background(), req)
+		errs <- err
+	}()
+	late := p.read()
+
+	var te *TimeoutError
+	if err := <-errs; !errors.As(err, &te) || te.Request != req {
+		t.Fatalf("got %v, want a TimeoutError with the original submit_sm", err)
+	}
+	select {
+	case got := <-expired:
+		if got != req {
+			t.Errorf("OnExpire got %p, want the original %p", got, req)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("OnExpire not called")
+	}
+	if n := c.InFlight(); n != 0 {
+		t.Errorf("%d requests in flight", n)
+	}
+
+	// a late response is dropped and the freed slot takes a new request
+	p.answer(late, pdu.ESME_ROK)
+	go func() {
+		_, err := c.Submit(context.Background(), submitSM("b"))
+		errs <- err
+	}()
+	p.answer(p.read(), pdu.ESME_ROK)
+	if err := <-errs; err != nil {
+		t.Errorf("after expiry: %v", err)
+	}
+}